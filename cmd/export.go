@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"certificate/internal/domain"
+	"certificate/internal/export"
+	"certificate/internal/ports"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Подкоманда export: выгрузка завершенных регистраций в файл или stdout, например для cron.
+// Возвращает код завершения процесса.
+func runExport(svc ports.RegistrationService, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	from := flags.String("from", "", "начало периода, YYYY-MM-DD")
+	to := flags.String("to", "", "конец периода включительно, YYYY-MM-DD")
+	campaign := flags.String("campaign", "", "код кампании")
+	formatName := flags.String("format", "csv", "формат файла: csv или xlsx")
+	out := flags.String("out", "", "путь к файлу, по умолчанию stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		slog.Error("Неверный формат выгрузки", "error", err)
+		return 2
+	}
+
+	filter := domain.ExportFilter{CampaignCode: *campaign}
+	if filter.From, err = parseDate(*from); err != nil {
+		slog.Error("Неверная дата начала периода", "error", err)
+		return 2
+	}
+	if filter.To, err = parseDate(*to); err != nil {
+		slog.Error("Неверная дата конца периода", "error", err)
+		return 2
+	}
+
+	rows, err := svc.ExportRegistrations(filter)
+	if err != nil {
+		slog.Error("Ошибка при выгрузке регистраций", "error", err)
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, rows); err != nil {
+		slog.Error("Ошибка при формировании файла выгрузки", "error", err)
+		return 1
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+	} else {
+		err = os.WriteFile(*out, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		slog.Error("Ошибка при записи выгрузки", "error", err)
+		return 1
+	}
+
+	slog.Info("Выгрузка завершена", "rows", len(rows))
+	return 0
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ожидается дата в формате YYYY-MM-DD: %w", err)
+	}
+	return t, nil
+}
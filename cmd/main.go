@@ -4,10 +4,14 @@ import (
 	"certificate/internal/adapters"
 	"certificate/internal/config"
 	"certificate/internal/delivery"
+	"certificate/internal/phone"
 	"certificate/internal/services"
+	"context"
 	"log/slog"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 )
 
 func main() {
@@ -26,20 +30,75 @@ func main() {
 
 	slog.Info("Успешное подключение к БД")
 
-	api := adapters.NewPosterAPI(cfg.PosterToken)
-	svc := services.NewRegistrationService(repo, api)
+	keys := map[string][]byte{cfg.EncryptionKeyID: cfg.EncryptionKey}
+	for id, key := range cfg.OldEncryptionKeys {
+		keys[id] = key
+	}
+	keyring, err := services.NewKeyring(cfg.EncryptionKeyID, keys)
+	if err != nil {
+		slog.Error("Ошибка инициализации ключей шифрования:", "error", err)
+		os.Exit(1)
+	}
+
+	tokens, err := services.NewTokenGenerator(cfg.TokenLength, cfg.TokenAlphabet)
+	if err != nil {
+		slog.Error("Ошибка настройки генератора токенов:", "error", err)
+		os.Exit(1)
+	}
 
-	bot, err := delivery.NewBot(cfg.BotToken, svc, cfg.BaseURL, cfg.Admins)
+	phones, err := phone.NewParser(cfg.PhoneCountries)
+	if err != nil {
+		slog.Error("Ошибка настройки проверки телефонов:", "error", err)
+		os.Exit(1)
+	}
+
+	api := adapters.NewPosterAPI(cfg.PosterToken, phones)
+	svc := services.NewRegistrationService(repo, repo, repo, repo, repo, repo, repo, api, keyring, tokens, cfg.LinkTTL)
+
+	// Выгрузка регистраций без запуска бота и сервера: links-bot export -from 2024-05-01 -format xlsx -out file.xlsx
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		code := runExport(svc, os.Args[2:])
+		repo.Close()
+		os.Exit(code)
+	}
+
+	if err := svc.BootstrapOwners(cfg.Admins); err != nil {
+		slog.Error("Ошибка назначения владельцев бота:", "error", err)
+		os.Exit(1)
+	}
+
+	worker := services.NewRegistrationWorker(svc)
+
+	var webhook *delivery.Webhook
+	if cfg.WebhookURL != "" {
+		webhook, err = delivery.NewWebhook(cfg.WebhookURL, cfg.WebhookSecret)
+		if err != nil {
+			slog.Error("Ошибка настройки webhook:", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	bot, err := delivery.NewBot(cfg.BotToken, svc, cfg.BaseURL, cfg.NotifyChatID, phones, webhook)
 	if err != nil {
 		slog.Error("Ошибка запуска бота:", "error", err)
 		os.Exit(1)
 	}
+	svc.SetNotifier(bot)
 
-	server := delivery.NewHTTPServer(svc)
+	server := delivery.NewHTTPServer(svc, cfg.BaseURL, phones, cfg.MinAge)
 	server.ServeStaticFiles()
+	if webhook != nil {
+		server.HandleWebhook(webhook)
+	}
+	server.AddReadinessCheck("telegram", bot.Check)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
 
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
 
 	go func() {
 		defer wg.Done()
@@ -48,8 +107,62 @@ func main() {
 
 	go func() {
 		defer wg.Done()
-		server.Start(":" + cfg.ServerPort)
+		if err := server.Start(":" + cfg.ServerPort); err != nil {
+			slog.Error("Ошибка HTTP-сервера:", "error", err)
+			stop()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		worker.Run(workerCtx)
 	}()
 
-	wg.Wait()
+	<-ctx.Done()
+	slog.Info("Остановка приложения", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Сначала перестаем принимать запросы и ждем начатые регистрации,
+	// затем останавливаем бота и фоновый обработчик задач
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP-сервер не успел завершить запросы:", "error", err)
+	}
+	go bot.Stop()
+	stopWorker()
+
+	// БД закрывается, только когда с ней никто не работает. Если кто-то не успел
+	// завершиться, БД остается открытой до выхода процесса: SQLite восстановится при запуске.
+	drained := true
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		slog.Error("Бот или обработчик задач не остановились вовремя")
+		drained = false
+	}
+
+	if err := server.Wait(shutdownCtx); err != nil {
+		slog.Error("HTTP-запросы не завершились до остановки:", "error", err)
+		drained = false
+	}
+	if err := bot.Wait(shutdownCtx); err != nil {
+		slog.Error("Обработчики команд бота не завершились до остановки:", "error", err)
+		drained = false
+	}
+	if err := svc.Wait(shutdownCtx); err != nil {
+		slog.Error("Не все уведомления отправлены до остановки:", "error", err)
+		drained = false
+	}
+
+	if !drained {
+		slog.Error("БД не закрыта: остались незавершенные обработчики")
+	} else if err := repo.Close(); err != nil {
+		slog.Error("Ошибка при закрытии БД:", "error", err)
+	}
+	slog.Info("Приложение остановлено")
 }
@@ -3,14 +3,24 @@ package adapters
 import (
 	"bytes"
 	"certificate/internal/domain"
+	"certificate/internal/metrics"
+	"certificate/internal/phone"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Сколько клиентов запрашивается у Poster за одну страницу поиска
+const clientsPageSize = 100
+
 // Client — структура данных клиента
 type posterClient struct {
 	ClientName     string `json:"client_name"`
@@ -33,24 +43,29 @@ type PosterAPI struct {
 	BaseURL string
 	Token   string
 	Client  *http.Client
+
+	phones *phone.Parser
+	cache  *clientCache
 }
 
 // NewPosterAPI создает новый экземпляр PosterAPI
-func NewPosterAPI(token string) *PosterAPI {
+func NewPosterAPI(token string, phones *phone.Parser) *PosterAPI {
 	return &PosterAPI{
 		BaseURL: "https://joinposter.com/api/",
 		Token:   token,
 		Client:  &http.Client{},
+		phones:  phones,
+		cache:   newClientCache(clientCacheTTL),
 	}
 }
 
 // ChangeClientBonus изменяет количество бонусов у клиента
-func (p *PosterAPI) ChangeClientBonus(clientID int) error {
-	url := fmt.Sprintf("%sclients.changeClientBonus?token=%s", p.BaseURL, p.Token)
+func (p *PosterAPI) ChangeClientBonus(clientID, amount int) error {
+	endpoint := fmt.Sprintf("%sclients.changeClientBonus?token=%s", p.BaseURL, p.Token)
 
 	requestBody := BonusUpdateRequest{
 		ClientID: clientID,
-		Count:    1000,
+		Count:    amount,
 	}
 
 	payload, err := json.Marshal(requestBody)
@@ -59,18 +74,18 @@ func (p *PosterAPI) ChangeClientBonus(clientID int) error {
 		return err
 	}
 
-	slog.Info("Отправка запроса на изменение бонусов", "clientID", clientID, "count", requestBody.Count, "url", url)
+	slog.Info("Отправка запроса на изменение бонусов", "clientID", clientID, "count", requestBody.Count)
 
-	resp, err := p.Client.Post(url, "application/json", bytes.NewBuffer(payload))
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(payload))
 	if err != nil {
-		slog.Error("Ошибка при выполнении запроса", "error", err)
+		err = redactURLError(err)
+		slog.Error("Ошибка создания HTTP-запроса", "error", err)
 		return err
 	}
-	defer resp.Body.Close()
+	req.Header.Set("Content-Type", "application/json")
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := p.do("clients.changeClientBonus", req)
 	if err != nil {
-		slog.Error("Ошибка чтения ответа", "error", err)
 		return err
 	}
 
@@ -78,19 +93,10 @@ func (p *PosterAPI) ChangeClientBonus(clientID int) error {
 	return nil
 }
 
-// CreateClient создает нового клиента, если его нет в базе
+// CreateClient создает нового клиента. Наличие клиента с тем же телефоном
+// проверяется заранее через FindClientByPhone.
 func (p *PosterAPI) CreateClient(c domain.Client) (int, error) {
-	existingClientID, err := p.findClientByPhone(c.Phone)
-	if err != nil {
-		slog.Error("Ошибка при поиске клиента", "phone", c.Phone, "error", err)
-		return 0, fmt.Errorf("ошибка при поиске клиента: %w", err)
-	}
-	if existingClientID != 0 {
-		slog.Info("Клиент уже зарегистрирован", "phone", c.Phone, "clientID", existingClientID)
-		return existingClientID, nil
-	}
-
-	url := fmt.Sprintf("%sclients.createClient?token=%s", p.BaseURL, p.Token)
+	endpoint := fmt.Sprintf("%sclients.createClient?token=%s", p.BaseURL, p.Token)
 	posterC := toPosterClient(c)
 
 	clientData, err := json.Marshal(posterC)
@@ -99,73 +105,89 @@ func (p *PosterAPI) CreateClient(c domain.Client) (int, error) {
 		return 0, err
 	}
 
-	slog.Info("Отправка запроса на создание клиента", "client", posterC, "url", url)
+	slog.Info("Отправка запроса на создание клиента", "client", posterC)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(clientData))
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(clientData))
 	if err != nil {
+		err = redactURLError(err)
 		slog.Error("Ошибка создания HTTP-запроса", "error", err)
 		return 0, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := p.Client.Do(req)
-	if err != nil {
-		slog.Error("Ошибка при выполнении запроса", "error", err)
-		return 0, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := p.do("clients.createClient", req)
 	if err != nil {
-		slog.Error("Ошибка чтения ответа", "error", err)
 		return 0, err
 	}
 
-	var response struct {
-		Response int `json:"response"`
-	}
+	var response Response
 	if err := json.Unmarshal(body, &response); err != nil {
 		slog.Error("Ошибка при разборе ответа API", "error", err)
 		return 0, err
 	}
 
+	// Без идентификатора клиента дальше идти нельзя: бонусы ушли бы клиенту 0
+	if response.Response <= 0 {
+		return 0, &PosterError{
+			HTTPStatus: http.StatusOK,
+			Method:     "clients.createClient",
+			Message:    "response does not contain client id: " + string(body),
+		}
+	}
+
 	slog.Info("Клиент успешно создан", "clientID", response.Response)
+	p.cache.set(p.normalizePhone(c.Phone), response.Response)
 	return response.Response, nil
 }
 
-// findClientByPhone ищет клиента в базе Poster по номеру телефона
-func (p *PosterAPI) findClientByPhone(phone string) (int, error) {
-	url := fmt.Sprintf("%sclients.getClients?token=%s", p.BaseURL, p.Token)
+// FindClientByPhone ищет клиента в базе Poster по номеру телефона, 0 — клиент не найден.
+// Poster фильтрует клиентов по телефону на своей стороне, но может вернуть
+// частичные совпадения, поэтому номера дополнительно сравниваются после нормализации.
+func (p *PosterAPI) FindClientByPhone(phone string) (int, error) {
+	normalized := p.normalizePhone(phone)
+	if clientID, ok := p.cache.get(normalized); ok {
+		slog.Info("Клиент найден в кэше", "clientID", clientID)
+		return clientID, nil
+	}
 
-	slog.Info("Поиск клиента по номеру телефона", "phone", phone, "url", url)
+	slog.Info("Поиск клиента по номеру телефона", "phone", phone)
 
-	resp, err := p.Client.Get(url)
-	if err != nil {
-		slog.Error("Ошибка при выполнении запроса", "error", err)
-		return 0, err
-	}
-	defer resp.Body.Close()
+	for offset := 0; ; offset += clientsPageSize {
+		query := url.Values{}
+		query.Set("token", p.Token)
+		query.Set("phone", strings.TrimPrefix(normalized, "+"))
+		query.Set("num", strconv.Itoa(clientsPageSize))
+		query.Set("offset", strconv.Itoa(offset))
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Ошибка чтения ответа", "error", err)
-		return 0, err
-	}
+		req, err := http.NewRequest("GET", p.BaseURL+"clients.getClients?"+query.Encode(), nil)
+		if err != nil {
+			err = redactURLError(err)
+			slog.Error("Ошибка создания HTTP-запроса", "error", err)
+			return 0, err
+		}
 
-	var response struct {
-		Response []struct {
-			ClientID string `json:"client_id"`
-			Phone    string `json:"phone"`
-		} `json:"response"`
-	}
+		body, err := p.do("clients.getClients", req)
+		if err != nil {
+			return 0, err
+		}
 
-	if err := json.Unmarshal(body, &response); err != nil {
-		slog.Error("Ошибка при разборе ответа API", "error", err, "response_body", string(body))
-		return 0, err
-	}
+		var response struct {
+			Response []struct {
+				ClientID string `json:"client_id"`
+				Phone    string `json:"phone"`
+			} `json:"response"`
+		}
+
+		if err := json.Unmarshal(body, &response); err != nil {
+			slog.Error("Ошибка при разборе ответа API", "error", err)
+			return 0, err
+		}
+
+		for _, client := range response.Response {
+			if p.normalizePhone(client.Phone) != normalized {
+				continue
+			}
 
-	for _, client := range response.Response {
-		if client.Phone == phone {
 			clientID, err := strconv.Atoi(client.ClientID)
 			if err != nil {
 				slog.Error("Ошибка конвертации client_id в int", "client_id", client.ClientID, "error", err)
@@ -173,20 +195,99 @@ func (p *PosterAPI) findClientByPhone(phone string) (int, error) {
 			}
 
 			slog.Info("Клиент найден", "phone", phone, "clientID", clientID)
+			p.cache.set(normalized, clientID)
 			return clientID, nil
 		}
+
+		if len(response.Response) < clientsPageSize {
+			break
+		}
 	}
 
+	// «Не найден» не кэшируется: если CreateClient создаст клиента, но ответ
+	// потеряется, повторная попытка должна найти его в Poster, а не создать второго
 	slog.Warn("Клиент не найден", "phone", phone)
 	return 0, nil // Клиент не найден
 }
 
+// normalizePhone приводит номер к E.164. Номера, которые не удалось разобрать
+// (например, записанные в Poster вручную), сравниваются как есть.
+func (p *PosterAPI) normalizePhone(raw string) string {
+	normalized, err := p.phones.Normalize(raw)
+	if err != nil {
+		return strings.TrimSpace(raw)
+	}
+	return normalized
+}
+
+// Ping проверяет доступность Poster и действительность токена дешевым запросом списка групп клиентов
+func (p *PosterAPI) Ping(ctx context.Context) error {
+	query := url.Values{}
+	query.Set("token", p.Token)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.BaseURL+"clients.getGroups?"+query.Encode(), nil)
+	if err != nil {
+		return redactURLError(err)
+	}
+
+	_, err = p.do("clients.getGroups", req)
+	return err
+}
+
+// do выполняет запрос к Poster и возвращает тело ответа.
+// Ошибки HTTP и ошибки в теле ответа возвращаются как *PosterError.
+func (p *PosterAPI) do(method string, req *http.Request) (body []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObservePosterRequest(method, requestOutcome(err), time.Since(start))
+	}()
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		err = redactURLError(err)
+		slog.Error("Ошибка при выполнении запроса", "method", method, "error", err)
+		return nil, fmt.Errorf("poster %s: %w: %w", method, domain.ErrPosterServer, err)
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Error("Ошибка чтения ответа", "method", method, "error", err)
+		return nil, fmt.Errorf("poster %s: %w: %w", method, domain.ErrPosterServer, err)
+	}
+
+	if err := parsePosterError(method, resp.StatusCode, body); err != nil {
+		slog.Error("Poster API вернул ошибку", "method", method, "error", err)
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// requestOutcome возвращает результат запроса для метрик
+func requestOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, domain.ErrPosterAuth):
+		return "auth"
+	case errors.Is(err, domain.ErrPosterValidation):
+		return "validation"
+	case errors.Is(err, domain.ErrPosterRateLimit):
+		return "rate_limit"
+	case errors.Is(err, domain.ErrPosterServer):
+		return "unavailable"
+	default:
+		return "error"
+	}
+}
+
 // toPosterClient преобразует доменную модель в API-структуру
 func toPosterClient(c domain.Client) posterClient {
 	return posterClient{
 		ClientName:     c.Name,
 		ClientSex:      c.Sex,
-		ClientGroupsID: 2,
+		ClientGroupsID: c.GroupID,
 		Phone:          c.Phone,
 		Birthday:       c.Birthday,
 	}
@@ -0,0 +1,54 @@
+package adapters
+
+import (
+	"sync"
+	"time"
+)
+
+// Сколько хранится результат поиска клиента по телефону
+const clientCacheTTL = 5 * time.Minute
+
+type clientCacheEntry struct {
+	clientID  int
+	expiresAt time.Time
+}
+
+// clientCache — кэш найденных и созданных клиентов по нормализованному номеру телефона
+type clientCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]clientCacheEntry
+}
+
+func newClientCache(ttl time.Duration) *clientCache {
+	return &clientCache{ttl: ttl, entries: make(map[string]clientCacheEntry)}
+}
+
+func (c *clientCache) get(phone string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[phone]
+	if !ok {
+		return 0, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, phone)
+		return 0, false
+	}
+	return entry.clientID, true
+}
+
+func (c *clientCache) set(phone string, clientID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// Заодно убираем устаревшие записи, чтобы кэш не рос бесконечно
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[phone] = clientCacheEntry{clientID: clientID, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Код ошибки Poster для неверного или отозванного токена доступа
+const posterCodeInvalidToken = 10
+
+// PosterError — ошибка, которую вернул Poster API
+type PosterError struct {
+	HTTPStatus int
+	Code       int
+	Message    string
+	Method     string
+}
+
+func (e *PosterError) Error() string {
+	return fmt.Sprintf("poster %s: http %d, code %d: %s", e.Method, e.HTTPStatus, e.Code, e.Message)
+}
+
+// Unwrap позволяет сервису различать вид ошибки через errors.Is
+func (e *PosterError) Unwrap() error {
+	switch {
+	case e.HTTPStatus == http.StatusUnauthorized || e.HTTPStatus == http.StatusForbidden ||
+		e.Code == posterCodeInvalidToken:
+		return domain.ErrPosterAuth
+	case e.HTTPStatus == http.StatusTooManyRequests:
+		return domain.ErrPosterRateLimit
+	case e.HTTPStatus >= http.StatusInternalServerError:
+		return domain.ErrPosterServer
+	default:
+		return domain.ErrPosterValidation
+	}
+}
+
+// Poster возвращает ошибку либо объектом {"error": {"error": код, "message": ...}},
+// либо полями верхнего уровня {"error": код, "message": ...}
+type posterErrorBody struct {
+	Error   json.RawMessage `json:"error"`
+	Message string          `json:"message"`
+}
+
+// Разбор ошибки из ответа Poster. Возвращает nil, если ответ успешный.
+func parsePosterError(method string, status int, body []byte) error {
+	var parsed posterErrorBody
+	jsonErr := json.Unmarshal(body, &parsed)
+
+	hasError := jsonErr == nil && len(parsed.Error) > 0 && string(parsed.Error) != "null"
+	if !hasError && status < http.StatusBadRequest {
+		return nil
+	}
+
+	perr := &PosterError{HTTPStatus: status, Method: method, Message: parsed.Message}
+	if hasError {
+		var nested struct {
+			Error   int    `json:"error"`
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		var text string
+		switch {
+		case json.Unmarshal(parsed.Error, &nested) == nil:
+			perr.Code = max(nested.Error, nested.Code)
+			if nested.Message != "" {
+				perr.Message = nested.Message
+			}
+		case json.Unmarshal(parsed.Error, &perr.Code) == nil:
+		case json.Unmarshal(parsed.Error, &text) == nil:
+			perr.Message = text
+		}
+	}
+	if perr.Message == "" {
+		perr.Message = http.StatusText(status)
+	}
+
+	return perr
+}
+
+// Удаление параметров запроса из URL в ошибке запроса: в них передается токен
+// Poster, а текст ошибки попадает в логи, в last_error задачи и в сообщения
+// администраторам (/stuck_jobs, /retry_job)
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if i := strings.IndexByte(urlErr.URL, '?'); i >= 0 {
+			urlErr.URL = urlErr.URL[:i]
+		}
+	}
+	return err
+}
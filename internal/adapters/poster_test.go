@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"certificate/internal/phone"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Адрес, на котором никто не слушает
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestPosterTransportErrorHidesToken(t *testing.T) {
+	const token = "SECRET_TOKEN"
+	p := NewPosterAPI(token, nil)
+	p.BaseURL = "http://" + closedAddr(t) + "/api/"
+
+	err := p.Ping(context.Background())
+	if !errors.Is(err, domain.ErrPosterServer) {
+		t.Fatalf("Ping error = %v, want %v", err, domain.ErrPosterServer)
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("error contains token: %v", err)
+	}
+	if !strings.Contains(err.Error(), "clients.getGroups") {
+		t.Errorf("error lost the request path: %v", err)
+	}
+}
+
+func TestPosterRequestErrorHidesToken(t *testing.T) {
+	const token = "SECRET_TOKEN"
+	p := NewPosterAPI(token, nil)
+	// Управляющий символ в адресе не дает собрать запрос
+	p.BaseURL = "http://poster\x7f/api/"
+
+	_, err := p.CreateClient(domain.Client{Name: "Иван", Phone: "+77771234567"})
+	if err == nil {
+		t.Fatal("CreateClient succeeded, want error")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("error contains token: %v", err)
+	}
+}
+
+// Клиент создан в Poster, но ответ потерян: повторная попытка должна снова искать
+// клиента в Poster, а не взять «не найден» из кэша
+func TestFindClientByPhoneDoesNotCacheMiss(t *testing.T) {
+	var searches, creates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "clients.getClients"):
+			searches++
+			if creates > 0 {
+				fmt.Fprint(w, `{"response":[{"client_id":"15","phone":"+7 777 123 45 67"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"response":[]}`)
+		case strings.HasSuffix(r.URL.Path, "clients.createClient"):
+			creates++
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	countries, err := phone.ParseCountries(phone.DefaultCountries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	phones, err := phone.NewParser(countries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPosterAPI("token", phones)
+	p.BaseURL = server.URL + "/api/"
+
+	if id, err := p.FindClientByPhone("87771234567"); err != nil || id != 0 {
+		t.Fatalf("FindClientByPhone = %d, %v; want 0, nil", id, err)
+	}
+	if _, err := p.CreateClient(domain.Client{Name: "Иван", Phone: "+77771234567"}); !errors.Is(err, domain.ErrPosterServer) {
+		t.Fatalf("CreateClient error = %v, want %v", err, domain.ErrPosterServer)
+	}
+
+	id, err := p.FindClientByPhone("87771234567")
+	if err != nil || id != 15 {
+		t.Fatalf("second FindClientByPhone = %d, %v; want 15, nil", id, err)
+	}
+	if searches != 2 {
+		t.Errorf("searches = %d, want 2", searches)
+	}
+}
@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"database/sql"
+	"errors"
+)
+
+const adminColumns = "user_id, username, role, added_by, created_at"
+
+// Чтение строки таблицы admins в доменную модель
+func scanAdmin(row rowScanner) (*domain.Admin, error) {
+	a := &domain.Admin{}
+	var username sql.NullString
+	var addedBy sql.NullInt64
+	var createdAt sql.NullTime
+	if err := row.Scan(&a.ID, &username, &a.Role, &addedBy, &createdAt); err != nil {
+		return nil, err
+	}
+	a.Username = username.String
+	a.AddedBy = int(addedBy.Int64)
+	a.CreatedAt = createdAt.Time
+	return a, nil
+}
+
+// Добавление администратора или смена роли существующего
+func (r *SQLiteRepository) SaveAdmin(a *domain.Admin) error {
+	_, err := r.db.Exec(
+		`INSERT INTO admins (user_id, username, role, added_by, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			role = excluded.role,
+			username = COALESCE(excluded.username, admins.username)`,
+		a.ID, nullString(a.Username), a.Role, sql.NullInt64{Int64: int64(a.AddedBy), Valid: a.AddedBy != 0}, nullTime(a.CreatedAt),
+	)
+	return err
+}
+
+// Получение администратора по ID пользователя Telegram
+func (r *SQLiteRepository) GetAdmin(id int) (*domain.Admin, error) {
+	row := r.db.QueryRow("SELECT "+adminColumns+" FROM admins WHERE user_id = ?", id)
+	a, err := scanAdmin(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAdminNotFound
+	}
+	return a, err
+}
+
+// Список администраторов: сначала владельцы, затем по дате добавления
+func (r *SQLiteRepository) ListAdmins() ([]domain.Admin, error) {
+	rows, err := r.db.Query("SELECT " + adminColumns + " FROM admins ORDER BY role != 'owner', created_at, user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []domain.Admin
+	for rows.Next() {
+		a, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, *a)
+	}
+
+	return admins, rows.Err()
+}
+
+// Удаление администратора
+func (r *SQLiteRepository) DeleteAdmin(id int) error {
+	res, err := r.db.Exec("DELETE FROM admins WHERE user_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrAdminNotFound
+	}
+	return nil
+}
+
+// Количество владельцев
+func (r *SQLiteRepository) CountOwners() (int, error) {
+	var n int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM admins WHERE role = ?", domain.RoleOwner).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,46 @@
+package adapters
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Включение или отключение уведомлений о регистрациях для администратора
+func (r *SQLiteRepository) SetNotifications(adminID int, enabled bool) error {
+	_, err := r.db.Exec(
+		`INSERT INTO admin_settings (admin_id, notifications) VALUES (?, ?)
+		ON CONFLICT (admin_id) DO UPDATE SET notifications = excluded.notifications`,
+		adminID, enabled,
+	)
+	return err
+}
+
+// Получает ли администратор уведомления. Без сохраненной настройки уведомления включены.
+func (r *SQLiteRepository) NotificationsEnabled(adminID int) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRow("SELECT notifications FROM admin_settings WHERE admin_id = ?", adminID).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	return enabled, err
+}
+
+// Сохранение языка ответов бота для администратора
+func (r *SQLiteRepository) SetLanguage(adminID int, language string) error {
+	_, err := r.db.Exec(
+		`INSERT INTO admin_settings (admin_id, language) VALUES (?, ?)
+		ON CONFLICT (admin_id) DO UPDATE SET language = excluded.language`,
+		adminID, language,
+	)
+	return err
+}
+
+// Язык ответов бота, выбранный администратором. Пустая строка — язык не выбран.
+func (r *SQLiteRepository) Language(adminID int) (string, error) {
+	var language string
+	err := r.db.QueryRow("SELECT language FROM admin_settings WHERE admin_id = ?", adminID).Scan(&language)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return language, err
+}
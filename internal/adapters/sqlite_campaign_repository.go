@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"database/sql"
+	"errors"
+)
+
+const campaignColumns = "id, code, bonus_amount, client_group_id, success_text, language, created_at"
+
+// Чтение строки таблицы campaigns в доменную модель
+func scanCampaign(row rowScanner) (*domain.Campaign, error) {
+	c := &domain.Campaign{}
+	var createdAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.Code, &c.BonusAmount, &c.ClientGroupID, &c.SuccessText, &c.Language, &createdAt); err != nil {
+		return nil, err
+	}
+	c.CreatedAt = createdAt.Time
+	return c, nil
+}
+
+// Создание кампании
+func (r *SQLiteRepository) CreateCampaign(c *domain.Campaign) error {
+	res, err := r.db.Exec(
+		"INSERT INTO campaigns (code, bonus_amount, client_group_id, success_text, language, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		c.Code, c.BonusAmount, c.ClientGroupID, c.SuccessText, c.Language, nullTime(c.CreatedAt),
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrCampaignExists
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+	return nil
+}
+
+// Получение кампании по идентификатору
+func (r *SQLiteRepository) GetCampaign(id int) (*domain.Campaign, error) {
+	row := r.db.QueryRow("SELECT "+campaignColumns+" FROM campaigns WHERE id = ?", id)
+	return notFoundCampaign(scanCampaign(row))
+}
+
+// Получение кампании по коду
+func (r *SQLiteRepository) GetCampaignByCode(code string) (*domain.Campaign, error) {
+	row := r.db.QueryRow("SELECT "+campaignColumns+" FROM campaigns WHERE code = ?", code)
+	return notFoundCampaign(scanCampaign(row))
+}
+
+// Список всех кампаний
+func (r *SQLiteRepository) ListCampaigns() ([]domain.Campaign, error) {
+	rows, err := r.db.Query("SELECT " + campaignColumns + " FROM campaigns ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []domain.Campaign
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, *c)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// Смена языка страниц регистрации кампании
+func (r *SQLiteRepository) UpdateCampaignLanguage(id int, language string) error {
+	res, err := r.db.Exec("UPDATE campaigns SET language = ? WHERE id = ?", language, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrCampaignNotFound
+	}
+	return nil
+}
+
+func notFoundCampaign(c *domain.Campaign, err error) (*domain.Campaign, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCampaignNotFound
+	}
+	return c, err
+}
@@ -0,0 +1,99 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"database/sql"
+	"time"
+)
+
+// Запись событий воронки одной транзакцией
+func (r *SQLiteRepository) RecordEvents(events []domain.Event) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO events (type, token, campaign_id, reason, created_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		_, err := stmt.Exec(
+			string(e.Type), nullString(e.Token),
+			sql.NullInt64{Int64: int64(e.CampaignID), Valid: e.CampaignID != 0},
+			nullString(e.Reason), nullTime(e.CreatedAt),
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Количество ссылок, дошедших до каждого этапа, по дням (UTC) и кампаниям начиная с since.
+// Повторные события одной ссылки (например, несколько открытий) считаются один раз.
+func (r *SQLiteRepository) GetFunnelStats(since time.Time) ([]domain.FunnelStats, error) {
+	rows, err := r.db.Query(
+		`SELECT substr(e.created_at, 1, 10) AS day, COALESCE(e.campaign_id, 0), COALESCE(c.code, ''), e.type,
+		COUNT(DISTINCT COALESCE(e.token, e.id))
+		FROM events e LEFT JOIN campaigns c ON c.id = e.campaign_id
+		WHERE e.created_at >= ?
+		GROUP BY day, e.campaign_id, e.type
+		ORDER BY day, e.campaign_id`,
+		nullTime(since),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []domain.FunnelStats
+	for rows.Next() {
+		var row domain.FunnelStats
+		var eventType string
+		var count int
+		if err := rows.Scan(&row.Day, &row.CampaignID, &row.CampaignCode, &eventType, &count); err != nil {
+			return nil, err
+		}
+
+		// Строки отсортированы по дню и кампании, поэтому типы событий одной пары идут подряд
+		if n := len(stats); n > 0 && stats[n-1].Day == row.Day && stats[n-1].CampaignID == row.CampaignID {
+			stats[n-1].Counts[domain.EventType(eventType)] = count
+			continue
+		}
+		row.Counts = map[domain.EventType]int{domain.EventType(eventType): count}
+		stats = append(stats, row)
+	}
+
+	return stats, rows.Err()
+}
+
+// Самые частые причины неудачных регистраций начиная с since
+func (r *SQLiteRepository) GetFailureReasons(since time.Time, limit int) ([]domain.ReasonCount, error) {
+	rows, err := r.db.Query(
+		`SELECT COALESCE(reason, ''), COUNT(*) AS n FROM events
+		WHERE type = ? AND created_at >= ?
+		GROUP BY reason ORDER BY n DESC LIMIT ?`,
+		string(domain.EventRegistrationFailed), nullTime(since), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reasons []domain.ReasonCount
+	for rows.Next() {
+		var rc domain.ReasonCount
+		if err := rows.Scan(&rc.Reason, &rc.Count); err != nil {
+			return nil, err
+		}
+		reasons = append(reasons, rc)
+	}
+
+	return reasons, rows.Err()
+}
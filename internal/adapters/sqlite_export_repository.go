@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Завершенные регистрации для выгрузки: ссылка, кампания и данные клиента.
+// Нулевые from, to и campaignID — без ограничения.
+func (r *SQLiteRepository) GetRegistrationsForExport(from, to time.Time, campaignID int) ([]domain.ExportRow, error) {
+	query := `SELECT r.token, COALESCE(c.code, ''), r.batch_label, r.issued_by, r.issued_by_username, r.created_at,
+		u.created_at, u.username, u.phone, COALESCE(j.birthday, ''), u.client_id
+		FROM registrations r
+		JOIN token_usage u ON u.token = r.token
+		LEFT JOIN campaigns c ON c.id = COALESCE(r.campaign_id, ?)
+		LEFT JOIN registration_jobs j ON j.token = r.token`
+
+	var where []string
+	args := []any{domain.DefaultCampaignID}
+	if !from.IsZero() {
+		where = append(where, "u.created_at >= ?")
+		args = append(args, nullTime(from))
+	}
+	if !to.IsZero() {
+		where = append(where, "u.created_at < ?")
+		args = append(args, nullTime(to))
+	}
+	if campaignID != 0 {
+		where = append(where, "COALESCE(r.campaign_id, ?) = ?")
+		args = append(args, domain.DefaultCampaignID, campaignID)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY u.id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []domain.ExportRow
+	for rows.Next() {
+		var row domain.ExportRow
+		var batchLabel, issuedByUsername sql.NullString
+		var issuedBy, clientID sql.NullInt64
+		var issuedAt, registeredAt sql.NullTime
+		err := rows.Scan(
+			&row.Token, &row.CampaignCode, &batchLabel, &issuedBy, &issuedByUsername, &issuedAt,
+			&registeredAt, &row.Name, &row.Phone, &row.Birthday, &clientID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		row.BatchLabel = batchLabel.String
+		row.IssuedBy = int(issuedBy.Int64)
+		row.IssuedByUsername = issuedByUsername.String
+		row.IssuedAt = issuedAt.Time
+		row.RegisteredAt = registeredAt.Time
+		row.ClientID = int(clientID.Int64)
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"database/sql/driver"
+	"strings"
+	"unicode"
+
+	"modernc.org/sqlite"
+)
+
+// Встроенные LOWER и LIKE в SQLite учитывают регистр только для латиницы,
+// поэтому для поиска по имени на кириллице регистрируются свои функции
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return strings.ToLower(s), nil
+	})
+
+	// Только цифры номера телефона, чтобы сравнивать номера в разных форматах
+	sqlite.MustRegisterDeterministicScalarFunction("phone_digits", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		return strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, s), nil
+	})
+}
@@ -0,0 +1,206 @@
+package adapters
+
+import (
+	"certificate/internal/domain"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const jobColumns = "id, token, name, phone, birthday, campaign_id, state, failed, client_id, client_existed, " +
+	"attempts, last_error, next_attempt_at, created_at, updated_at"
+
+// Чтение строки таблицы registration_jobs в доменную модель
+func scanJob(row rowScanner) (*domain.RegistrationJob, error) {
+	job := &domain.RegistrationJob{}
+	var state string
+	var clientID sql.NullInt64
+	var lastError sql.NullString
+	var nextAttemptAt, createdAt, updatedAt sql.NullTime
+	err := row.Scan(
+		&job.ID, &job.Token, &job.Name, &job.Phone, &job.Birthday, &job.CampaignID, &state, &job.Failed, &clientID, &job.ClientExisted,
+		&job.Attempts, &lastError, &nextAttemptAt, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.State = domain.JobState(state)
+	job.ClientID = int(clientID.Int64)
+	job.LastError = lastError.String
+	job.NextAttemptAt = nextAttemptAt.Time
+	job.CreatedAt = createdAt.Time
+	job.UpdatedAt = updatedAt.Time
+	return job, nil
+}
+
+// Захват токена и постановка регистрации в очередь в одной транзакции
+func (r *SQLiteRepository) EnqueueRegistration(job *domain.RegistrationJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := claimToken(tx, job.Token); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	res, err := tx.Exec(
+		`INSERT INTO registration_jobs (token, name, phone, birthday, campaign_id, state, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.Token, job.Name, job.Phone, job.Birthday, job.CampaignID, string(job.State),
+		nullTime(job.NextAttemptAt), nullTime(job.CreatedAt), nullTime(job.UpdatedAt),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	job.ID = int(id)
+
+	return tx.Commit()
+}
+
+// Захват задачи на время обработки. Строка обновляется, только если с момента
+// чтения задачи ее никто не менял (совпадают шаг и время обновления), иначе
+// возвращается ErrJobBusy: задачу уже обработал или обрабатывает кто-то другой.
+// До leaseUntil фоновый обработчик задачу не берет.
+func (r *SQLiteRepository) ClaimJob(job *domain.RegistrationJob, now, leaseUntil time.Time) error {
+	res, err := r.db.Exec(
+		`UPDATE registration_jobs SET failed = ?, attempts = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ? AND state = ? AND updated_at IS ?`,
+		job.Failed, job.Attempts, nullTime(leaseUntil), nullTime(now),
+		job.ID, string(job.State), nullTime(job.UpdatedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrJobBusy
+	}
+
+	job.NextAttemptAt = leaseUntil
+	job.UpdatedAt = now
+	return nil
+}
+
+// Сохранение состояния задачи после очередного шага или неудачной попытки
+func (r *SQLiteRepository) UpdateJob(job *domain.RegistrationJob) error {
+	return updateJob(r.db, job)
+}
+
+func updateJob(db dbtx, job *domain.RegistrationJob) error {
+	_, err := db.Exec(
+		`UPDATE registration_jobs SET state = ?, failed = ?, client_id = ?, client_existed = ?, attempts = ?,
+		last_error = ?, next_attempt_at = ?, updated_at = ? WHERE id = ?`,
+		string(job.State), job.Failed, sql.NullInt64{Int64: int64(job.ClientID), Valid: job.ClientID != 0},
+		job.ClientExisted, job.Attempts, nullString(job.LastError), nullTime(job.NextAttemptAt), nullTime(job.UpdatedAt), job.ID,
+	)
+	return err
+}
+
+// Завершение задачи: запись данных клиента, использовавшего токен
+func (r *SQLiteRepository) CompleteJob(job *domain.RegistrationJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := updateJob(tx, job); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO token_usage (token, username, phone, client_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		job.Token, job.Name, job.Phone, job.ClientID, nullTime(job.UpdatedAt),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Отмена задачи, по которой в Poster еще ничего не сделано: задача удаляется,
+// а токен снова становится доступным для регистрации
+func (r *SQLiteRepository) CancelJob(job *domain.RegistrationJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM registration_jobs WHERE id = ?", job.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE registrations SET used = FALSE WHERE token = ?", job.Token); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Получение задачи по идентификатору
+func (r *SQLiteRepository) GetJob(id int) (*domain.RegistrationJob, error) {
+	row := r.db.QueryRow("SELECT "+jobColumns+" FROM registration_jobs WHERE id = ?", id)
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, err
+}
+
+// Задачи, время очередной попытки которых уже наступило
+func (r *SQLiteRepository) GetDueJobs(now time.Time, limit int) ([]domain.RegistrationJob, error) {
+	rows, err := r.db.Query(
+		"SELECT "+jobColumns+` FROM registration_jobs
+		WHERE state != ? AND failed = FALSE AND next_attempt_at <= ?
+		ORDER BY next_attempt_at LIMIT ?`,
+		string(domain.JobDone), nullTime(now), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanJobs(rows)
+}
+
+// Незавершенные задачи, у которых уже были неудачные попытки
+func (r *SQLiteRepository) GetStuckJobs() ([]domain.RegistrationJob, error) {
+	rows, err := r.db.Query(
+		"SELECT "+jobColumns+" FROM registration_jobs WHERE state != ? AND (failed = TRUE OR attempts > 0) ORDER BY id",
+		string(domain.JobDone),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanJobs(rows)
+}
+
+func scanJobs(rows *sql.Rows) ([]domain.RegistrationJob, error) {
+	defer rows.Close()
+
+	var jobs []domain.RegistrationJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
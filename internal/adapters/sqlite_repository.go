@@ -2,23 +2,31 @@ package adapters
 
 import (
 	"certificate/internal/domain"
-	"certificate/internal/ports"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
+// Сколько ждать освобождения БД другим соединением, прежде чем вернуть SQLITE_BUSY
+const busyTimeout = 5 * time.Second
+
 type SQLiteRepository struct {
 	db *sql.DB
 }
 
-func NewSQLiteRepository(dbPath string) (ports.RegistrationRepository, error) {
-	db, err := sql.Open("sqlite", dbPath)
+func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath))
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +39,7 @@ func NewSQLiteRepository(dbPath string) (ports.RegistrationRepository, error) {
 		slog.Error("Ошибка при создании мигратора: %v", "error", err)
 		return nil, err
 	}
+	defer m.Close()
 
 	err = m.Up()
 	if err != nil && err != migrate.ErrNoChange {
@@ -41,95 +50,345 @@ func NewSQLiteRepository(dbPath string) (ports.RegistrationRepository, error) {
 	return &SQLiteRepository{db: db}, nil
 }
 
+// Проверка доступности БД чтением, чтобы частые проверки /healthz и /readyz
+// не захватывали блокировку записи и не мешали регистрациям
+func (r *SQLiteRepository) Ping(ctx context.Context) error {
+	var n int
+	return r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&n)
+}
+
+// Строка подключения с ожиданием занятой БД: без него параллельные запросы
+// сразу получают SQLITE_BUSY
+func sqliteDSN(dbPath string) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)", dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// Закрытие соединения с БД
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+const registrationColumns = "id, token, used, created_at, expires_at, batch_label, campaign_id, " +
+	"revoked, revoked_reason, revoked_by, revoked_at, issued_by, issued_by_username"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Чтение строки таблицы registrations в доменную модель
+func scanRegistration(row rowScanner) (*domain.Registration, error) {
+	reg := &domain.Registration{}
+	var createdAt, expiresAt, revokedAt sql.NullTime
+	var batchLabel, revokedReason, issuedByUsername sql.NullString
+	var campaignID, revokedBy, issuedBy sql.NullInt64
+	var revoked sql.NullBool
+	err := row.Scan(
+		&reg.ID, &reg.Token, &reg.Used, &createdAt, &expiresAt, &batchLabel, &campaignID,
+		&revoked, &revokedReason, &revokedBy, &revokedAt, &issuedBy, &issuedByUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+	reg.CreatedAt = createdAt.Time
+	reg.ExpiresAt = expiresAt.Time
+	reg.BatchLabel = batchLabel.String
+	reg.CampaignID = int(campaignID.Int64)
+	if !campaignID.Valid {
+		reg.CampaignID = domain.DefaultCampaignID
+	}
+	reg.Revoked = revoked.Bool
+	reg.RevokedReason = revokedReason.String
+	reg.RevokedBy = int(revokedBy.Int64)
+	reg.RevokedAt = revokedAt.Time
+	reg.IssuedBy = int(issuedBy.Int64)
+	reg.IssuedByUsername = issuedByUsername.String
+	return reg, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Нарушение ограничения UNIQUE (например, повтор токена)
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// Время в БД храним в UTC, чтобы строки сравнивались корректно
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// Общий интерфейс *sql.DB и *sql.Tx
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 // Создание записи с токеном
-func (r *SQLiteRepository) Create(token string) error {
-	_, err := r.db.Exec("INSERT INTO registrations (token) VALUES (?)", token)
-	return err
+func (r *SQLiteRepository) Create(reg *domain.Registration) error {
+	return insertRegistration(r.db, reg)
+}
+
+// Создание пачки записей в одной транзакции: либо сохраняются все, либо ни одной
+func (r *SQLiteRepository) CreateBatch(regs []*domain.Registration) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, reg := range regs {
+		if err := insertRegistration(tx, reg); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertRegistration(db dbtx, reg *domain.Registration) error {
+	res, err := db.Exec(
+		`INSERT INTO registrations (token, created_at, expires_at, batch_label, campaign_id, issued_by, issued_by_username)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		reg.Token, nullTime(reg.CreatedAt), nullTime(reg.ExpiresAt), nullString(reg.BatchLabel), reg.CampaignID,
+		sql.NullInt64{Int64: int64(reg.IssuedBy), Valid: reg.IssuedBy != 0}, nullString(reg.IssuedByUsername),
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrTokenExists
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	reg.ID = int(id)
+	return nil
 }
 
 // Получение токена по значению
 func (r *SQLiteRepository) GetByToken(token string) (*domain.Registration, error) {
-	row := r.db.QueryRow("SELECT id, token, used FROM registrations WHERE token = ?", token)
-	reg := &domain.Registration{}
-	err := row.Scan(&reg.ID, &reg.Token, &reg.Used)
+	row := r.db.QueryRow("SELECT "+registrationColumns+" FROM registrations WHERE token = ?", token)
+	reg, err := scanRegistration(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return reg, nil
 }
 
-// Отметка токена как использованного и запись кто это сделал
-func (r *SQLiteRepository) MarkTokenUsed(token, name, phone string) error {
-	tx, err := r.db.Begin()
+// Получение токена по идентификатору записи
+func (r *SQLiteRepository) GetByID(id int) (*domain.Registration, error) {
+	row := r.db.QueryRow("SELECT "+registrationColumns+" FROM registrations WHERE id = ?", id)
+	reg, err := scanRegistration(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTokenNotFound
+	}
+	return reg, err
+}
+
+// Атомарный захват токена: из нескольких одновременных запросов успешен только один
+func claimToken(db dbtx, token string) error {
+	res, err := db.Exec("UPDATE registrations SET used = TRUE WHERE token = ? AND used = FALSE AND revoked = FALSE", token)
 	if err != nil {
 		return err
 	}
+	return checkTokenUpdated(db, res, token)
+}
 
-	// Обновляем статус токена на "использованный"
-	_, err = tx.Exec("UPDATE registrations SET used = TRUE WHERE token = ?", token)
+// Отзыв неиспользованного токена администратором
+func (r *SQLiteRepository) RevokeToken(token, reason string, adminID int, revokedAt time.Time) error {
+	res, err := r.db.Exec(
+		`UPDATE registrations SET revoked = TRUE, revoked_reason = ?, revoked_by = ?, revoked_at = ?
+		WHERE token = ? AND used = FALSE AND revoked = FALSE`,
+		nullString(reason), adminID, nullTime(revokedAt), token,
+	)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
+	return checkTokenUpdated(r.db, res, token)
+}
 
-	// Записываем информацию о пользователе, который использовал токен
-	_, err = tx.Exec("INSERT INTO token_usage (token, username, phone) VALUES (?, ?, ?)", token, name, phone)
+// Если условное обновление не затронуло ни одной строки, выясняем причину
+func checkTokenUpdated(db dbtx, res sql.Result, token string) error {
+	affected, err := res.RowsAffected()
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
+	if affected > 0 {
+		return nil
+	}
 
-	// Фиксируем изменения в БД
-	return tx.Commit()
+	row := db.QueryRow("SELECT "+registrationColumns+" FROM registrations WHERE token = ?", token)
+	reg, err := scanRegistration(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTokenNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if reg.Revoked {
+		return domain.ErrTokenRevoked
+	}
+	return domain.ErrTokenUsed
 }
 
 // Получить данные пользователя, который использовал токен
 func (r *SQLiteRepository) GetTokenUsage(token string) (*domain.TokenUsage, error) {
-	row := r.db.QueryRow("SELECT id, token, username, phone FROM token_usage WHERE token = ?", token)
+	row := r.db.QueryRow("SELECT id, token, username, phone, client_id, created_at FROM token_usage WHERE token = ?", token)
 	usage := &domain.TokenUsage{}
-	err := row.Scan(&usage.ID, &usage.Token, &usage.Username, &usage.Phone)
+	var clientID sql.NullInt64
+	var createdAt sql.NullTime
+	err := row.Scan(&usage.ID, &usage.Token, &usage.Username, &usage.Phone, &clientID, &createdAt)
 	if err != nil {
 		return nil, err
 	}
+	usage.ClientID = int(clientID.Int64)
+	usage.CreatedAt = createdAt.Time
 	return usage, nil
 }
 
-// Получить список использованных токенов
-func (r *SQLiteRepository) GetUsedTokens() ([]domain.Registration, error) {
-	rows, err := r.db.Query("SELECT id, token, used FROM registrations WHERE used = TRUE")
+const matchColumns = "u.id, u.token, u.username, u.phone, u.client_id, u.created_at, r.issued_by, r.issued_by_username"
+
+// Поиск регистраций по номеру телефона в формате E.164. Записи, сохраненные до нормализации
+// номеров, сравниваются по последним 10 цифрам.
+func (r *SQLiteRepository) FindUsageByPhone(phone string, limit int) ([]domain.RegistrationMatch, error) {
+	digits := strings.TrimPrefix(phone, "+")
+	tail := digits[max(len(digits)-10, 0):]
+	rows, err := r.db.Query(
+		"SELECT "+matchColumns+` FROM token_usage u LEFT JOIN registrations r ON r.token = u.token
+		WHERE u.phone = ? OR substr(phone_digits(u.phone), -10) = ?
+		ORDER BY u.id DESC LIMIT ?`,
+		phone, tail, limit,
+	)
 	if err != nil {
 		return nil, err
 	}
+	return scanMatches(rows)
+}
+
+// Поиск регистраций по части имени без учета регистра
+func (r *SQLiteRepository) FindUsageByName(name string, limit int) ([]domain.RegistrationMatch, error) {
+	rows, err := r.db.Query(
+		"SELECT "+matchColumns+` FROM token_usage u LEFT JOIN registrations r ON r.token = u.token
+		WHERE instr(unicode_lower(u.username), ?) > 0
+		ORDER BY u.id DESC LIMIT ?`,
+		strings.ToLower(name), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanMatches(rows)
+}
+
+func scanMatches(rows *sql.Rows) ([]domain.RegistrationMatch, error) {
 	defer rows.Close()
 
-	var tokens []domain.Registration
+	var matches []domain.RegistrationMatch
 	for rows.Next() {
-		var reg domain.Registration
-		if err := rows.Scan(&reg.ID, &reg.Token, &reg.Used); err != nil {
+		var m domain.RegistrationMatch
+		var clientID, issuedBy sql.NullInt64
+		var createdAt sql.NullTime
+		var issuedByUsername sql.NullString
+		err := rows.Scan(
+			&m.Usage.ID, &m.Usage.Token, &m.Usage.Username, &m.Usage.Phone, &clientID, &createdAt,
+			&issuedBy, &issuedByUsername,
+		)
+		if err != nil {
 			return nil, err
 		}
-		tokens = append(tokens, reg)
+		m.Usage.ClientID = int(clientID.Int64)
+		m.Usage.CreatedAt = createdAt.Time
+		m.IssuedBy = int(issuedBy.Int64)
+		m.IssuedByUsername = issuedByUsername.String
+		matches = append(matches, m)
+	}
+
+	return matches, rows.Err()
+}
+
+// Получить список использованных токенов
+func (r *SQLiteRepository) GetUsedTokens() ([]domain.Registration, error) {
+	rows, err := r.db.Query("SELECT " + registrationColumns + " FROM registrations WHERE used = TRUE")
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return tokens, nil
+	return scanRegistrations(rows)
 }
 
 // Получить список неиспользованных токенов
 func (r *SQLiteRepository) GetUnusedTokens() ([]domain.Registration, error) {
-	rows, err := r.db.Query("SELECT id, token, used FROM registrations WHERE used = FALSE")
+	rows, err := r.db.Query("SELECT " + registrationColumns + " FROM registrations WHERE used = FALSE AND revoked = FALSE")
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanRegistrations(rows)
+}
+
+// Страница использованных или действующих (неиспользованных и не отозванных) токенов,
+// новые сначала, и общее количество таких токенов
+func (r *SQLiteRepository) GetTokensPage(used bool, offset, limit int) ([]domain.Registration, int, error) {
+	where := " WHERE used = TRUE"
+	if !used {
+		where = " WHERE used = FALSE AND revoked = FALSE"
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM registrations" + where).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query("SELECT "+registrationColumns+" FROM registrations"+where+" ORDER BY id DESC LIMIT ? OFFSET ?", limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tokens, err := scanRegistrations(rows)
+	return tokens, total, err
+}
+
+// Последние ссылки, выданные администратором
+func (r *SQLiteRepository) GetTokensIssuedBy(adminID, limit int) ([]domain.Registration, error) {
+	rows, err := r.db.Query(
+		"SELECT "+registrationColumns+" FROM registrations WHERE issued_by = ? ORDER BY created_at DESC, id DESC LIMIT ?",
+		adminID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRegistrations(rows)
+}
+
+// Чтение списка строк таблицы registrations
+func scanRegistrations(rows *sql.Rows) ([]domain.Registration, error) {
 	var tokens []domain.Registration
 	for rows.Next() {
-		var reg domain.Registration
-		if err := rows.Scan(&reg.ID, &reg.Token, &reg.Used); err != nil {
+		reg, err := scanRegistration(rows)
+		if err != nil {
 			return nil, err
 		}
-		tokens = append(tokens, reg)
+		tokens = append(tokens, *reg)
 	}
 
-	return tokens, nil
+	return tokens, rows.Err()
 }
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"certificate/internal/phone"
 	"fmt"
-	"log"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,23 +20,97 @@ type Config struct {
 	BaseURL       string
 	PosterToken   string
 	EncryptionKey []byte
+	// Идентификатор текущего ключа, попадает в префикс каждого токена
+	EncryptionKeyID string
+	// Выведенные из использования ключи: ими только расшифровываются старые ссылки
+	OldEncryptionKeys map[string][]byte
+	// Владельцы, которые назначаются при первом запуске, пока в БД нет ни одного владельца
 	Admins        []int
+	LinkTTL       time.Duration
+	TokenLength   int
+	TokenAlphabet string
+	// Страны и коды операторов, номера которых принимаются в форме
+	PhoneCountries []phone.Country
+	// Минимальный возраст для регистрации, 0 — без ограничения
+	MinAge int
+	// Чат или канал для уведомлений о регистрациях, 0 — личные сообщения администраторам
+	NotifyChatID int64
+	// Адрес приема обновлений Telegram, пустой — long polling
+	WebhookURL string
+	// Секрет, который Telegram присылает с каждым обновлением
+	WebhookSecret string
+	// Сколько ждать завершения начатых запросов и задач при остановке
+	ShutdownTimeout time.Duration
 }
 
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
 	config := &Config{
-		ServerPort:    getEnv("PORT", "8080"),
-		DBPath:        getEnv("DB_PATH", "registration.db?mode=rwc"),
-		BotToken:      getEnv("BOT_TOKEN", ""),
-		BaseURL:       getEnv("BASE_URL", ""),
-		PosterToken:   getEnv("POSTER_TOKEN", ""),
-		EncryptionKey: []byte(getEnv("ENCRYPTION_KEY", "")),
+		ServerPort:      getEnv("PORT", "8080"),
+		DBPath:          getEnv("DB_PATH", "registration.db?mode=rwc"),
+		BotToken:        getEnv("BOT_TOKEN", ""),
+		BaseURL:         getEnv("BASE_URL", ""),
+		PosterToken:     getEnv("POSTER_TOKEN", ""),
+		EncryptionKey:   []byte(getEnv("ENCRYPTION_KEY", "")),
+		EncryptionKeyID: getEnv("ENCRYPTION_KEY_ID", "1"),
+		TokenAlphabet:   getEnv("TOKEN_ALPHABET", "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"),
 	}
 
-	adminsStr := getEnv("ADMINS", "")
-	config.Admins = parseAdmins(adminsStr)
+	tokenLength, err := strconv.Atoi(getEnv("TOKEN_LENGTH", "16"))
+	if err != nil {
+		return nil, fmt.Errorf("TOKEN_LENGTH задан некорректно: %w", err)
+	}
+	config.TokenLength = tokenLength
+
+	minAge, err := strconv.Atoi(getEnv("MIN_AGE", "14"))
+	if err != nil || minAge < 0 {
+		return nil, fmt.Errorf("MIN_AGE задан некорректно: %q", getEnv("MIN_AGE", ""))
+	}
+	config.MinAge = minAge
+
+	oldKeys, err := parseKeys(getEnv("ENCRYPTION_OLD_KEYS", ""))
+	if err != nil {
+		return nil, err
+	}
+	config.OldEncryptionKeys = oldKeys
+
+	linkTTL, err := time.ParseDuration(getEnv("LINK_TTL", "168h"))
+	if err != nil || linkTTL < 0 {
+		return nil, fmt.Errorf("LINK_TTL задан некорректно: %q", getEnv("LINK_TTL", ""))
+	}
+	config.LinkTTL = linkTTL
+
+	phoneCountries, err := phone.ParseCountries(getEnv("PHONE_COUNTRIES", phone.DefaultCountries))
+	if err != nil {
+		return nil, fmt.Errorf("PHONE_COUNTRIES задан некорректно: %w", err)
+	}
+	config.PhoneCountries = phoneCountries
+
+	if chatID := getEnv("NOTIFY_CHAT_ID", ""); chatID != "" {
+		config.NotifyChatID, err = strconv.ParseInt(chatID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("NOTIFY_CHAT_ID задан некорректно: %w", err)
+		}
+	}
+
+	config.ShutdownTimeout, err = time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil || config.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("SHUTDOWN_TIMEOUT задан некорректно: %q", getEnv("SHUTDOWN_TIMEOUT", ""))
+	}
+
+	config.WebhookURL = getEnv("WEBHOOK_URL", "")
+	config.WebhookSecret = getEnv("WEBHOOK_SECRET", "")
+	if config.WebhookURL != "" {
+		if err := validateWebhook(config.WebhookURL, config.WebhookSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	config.Admins, err = parseAdmins(getEnv("ADMINS", ""))
+	if err != nil {
+		return nil, err
+	}
 
 	// Проверка обязательных переменных
 	if config.BotToken == "" {
@@ -47,6 +125,9 @@ func LoadConfig() (*Config, error) {
 	if len(config.EncryptionKey) == 0 {
 		return nil, fmt.Errorf("ENCRYPTION_KEY не задан")
 	}
+	if _, exists := config.OldEncryptionKeys[config.EncryptionKeyID]; exists {
+		return nil, fmt.Errorf("ENCRYPTION_OLD_KEYS содержит текущий ключ %q", config.EncryptionKeyID)
+	}
 
 	return config, nil
 }
@@ -58,24 +139,57 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func parseAdmins(adminsStr string) []int {
+// Разбор списка ключей вида "id1:key1,id2:key2"
+func parseKeys(keysStr string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	if keysStr == "" {
+		return keys, nil
+	}
+
+	for _, p := range strings.Split(keysStr, ",") {
+		id, key, found := strings.Cut(strings.TrimSpace(p), ":")
+		if !found || id == "" || key == "" {
+			return nil, fmt.Errorf("ENCRYPTION_OLD_KEYS задан некорректно: ожидается id:key")
+		}
+		keys[id] = []byte(key)
+	}
+	return keys, nil
+}
+
+// Разбор списка ID администраторов через запятую
+func parseAdmins(adminsStr string) ([]int, error) {
 	var admins []int
-	if adminsStr == "" {
-		return admins
+	if strings.TrimSpace(adminsStr) == "" {
+		return admins, nil
 	}
 
-	parts := strings.Split(adminsStr, ",")
-	for _, p := range parts {
-		admins = append(admins, parseInt(p))
+	for _, p := range strings.Split(adminsStr, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("ADMINS задан некорректно: %q не является ID пользователя Telegram", strings.TrimSpace(p))
+		}
+		admins = append(admins, id)
 	}
-	return admins
+	return admins, nil
 }
 
-func parseInt(s string) int {
-	var i int
-	_, err := fmt.Sscanf(s, "%d", &i)
-	if err != nil {
-		log.Printf("Ошибка парсинга int: %v", err)
+// Секрет webhook: от 1 до 256 символов A-Z, a-z, 0-9, _ и -
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Проверка настроек webhook: HTTPS-адрес с путем и секрет допустимого формата
+func validateWebhook(webhookURL, secret string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("WEBHOOK_URL задан некорректно: ожидается адрес https://")
+	}
+	if u.Path == "" || u.Path == "/" {
+		return fmt.Errorf("WEBHOOK_URL задан некорректно: укажите путь, например https://example.com/telegram/webhook")
+	}
+	if secret == "" {
+		return fmt.Errorf("WEBHOOK_SECRET не задан")
+	}
+	if !webhookSecretPattern.MatchString(secret) {
+		return fmt.Errorf("WEBHOOK_SECRET задан некорректно: допустимы от 1 до 256 символов A-Z, a-z, 0-9, _ и -")
 	}
-	return i
+	return nil
 }
@@ -0,0 +1,57 @@
+package delivery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+
+	"github.com/tucnak/telebot"
+)
+
+// Ключи каталога с подписями ролей администраторов
+var roleLabels = map[domain.AdminRole]string{
+	domain.RoleOwner:    "role.owner",
+	domain.RoleManager:  "role.manager",
+	domain.RoleIssuer:   "role.issuer",
+	domain.RoleReadOnly: "role.readonly",
+}
+
+// Текст ответа /admins
+func formatAdmins(lang i18n.Lang, admins []domain.Admin) string {
+	var sb strings.Builder
+	sb.WriteString(lang.T("admins.title") + "\n")
+	for _, a := range admins {
+		fmt.Fprintf(&sb, "%s — %s\n", issuerName(lang, a.ID, a.Username), lang.T(roleLabels[a.Role]))
+	}
+	return sb.String()
+}
+
+// Разбор аргументов /add_admin ID роль [имя]. В ответ на пересланное
+// сообщение ID и имя берутся у его автора: /add_admin роль
+func parseAddAdminArgs(m *telebot.Message) (id int, username string, role domain.AdminRole, err error) {
+	args := strings.Fields(m.Payload)
+
+	if m.ReplyTo != nil && m.ReplyTo.OriginalSender != nil && len(args) == 1 {
+		role, err = domain.ParseAdminRole(args[0])
+		return m.ReplyTo.OriginalSender.ID, m.ReplyTo.OriginalSender.Username, role, err
+	}
+
+	if len(args) < 2 || len(args) > 3 {
+		return 0, "", "", fmt.Errorf("wrong number of arguments")
+	}
+	id, err = strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return 0, "", "", fmt.Errorf("invalid user id %q", args[0])
+	}
+	role, err = domain.ParseAdminRole(args[1])
+	if err != nil {
+		return 0, "", "", err
+	}
+	if len(args) == 3 {
+		username = strings.TrimPrefix(args[2], "@")
+	}
+	return id, username, role, nil
+}
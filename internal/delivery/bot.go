@@ -1,11 +1,26 @@
 package delivery
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 
+	"certificate/internal/domain"
+	"certificate/internal/export"
+	"certificate/internal/i18n"
+	"certificate/internal/metrics"
+	"certificate/internal/phone"
 	"certificate/internal/ports"
 
 	"github.com/tucnak/telebot"
@@ -15,142 +30,1039 @@ type Bot struct {
 	bot     *telebot.Bot
 	svc     ports.RegistrationService
 	baseURL string
-	admins  map[int]struct{}
+	// Чат или канал для уведомлений о регистрациях, 0 — личные сообщения администраторам
+	notifyChatID int64
+	// Шаги многошаговых команд по чатам
+	convs  *conversations
+	phones *phone.Parser
+	// Прием обновлений через HTTP-сервер, nil — long polling
+	webhook *Webhook
+	// Бот запущен и получает обновления
+	running atomic.Bool
+	// Начатые обработчики команд и кнопок
+	handlers inflight
 }
 
-func NewBot(token string, svc ports.RegistrationService, baseURL string, adminIDs []int) (*Bot, error) {
+func NewBot(token string, svc ports.RegistrationService, baseURL string, notifyChatID int64, phones *phone.Parser, webhook *Webhook) (*Bot, error) {
+	var poller telebot.Poller = &telebot.LongPoller{Timeout: 10 * time.Second}
+	if webhook != nil {
+		poller = webhook
+	}
+
 	b, err := telebot.NewBot(telebot.Settings{
 		Token:  token,
-		Poller: &telebot.LongPoller{Timeout: 10 * time.Second},
+		Poller: poller,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	adminMap := make(map[int]struct{}, len(adminIDs))
-	for _, id := range adminIDs {
-		adminMap[id] = struct{}{}
+	return &Bot{bot: b, svc: svc, baseURL: baseURL, notifyChatID: notifyChatID,
+		convs: newConversations(conversationTimeout), phones: phones, webhook: webhook}, nil
+}
+
+// Проверка, есть ли у пользователя право на действие
+func (b *Bot) can(userID int, perm domain.Permission) bool {
+	admin, err := b.svc.GetAdmin(userID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAdminNotFound) {
+			slog.Error("Ошибка при проверке прав администратора", "ID", userID, "error", err)
+		}
+		return false
 	}
+	return admin.Role.Can(perm)
+}
 
-	return &Bot{bot: b, svc: svc, baseURL: baseURL, admins: adminMap}, nil
+// Регистрация обработчика команды с подсчетом вызовов в метриках
+func (b *Bot) handle(command string, handler func(m *telebot.Message)) {
+	b.bot.Handle(command, func(m *telebot.Message) {
+		if !b.handlers.enter() {
+			return
+		}
+		defer b.handlers.leave()
+		metrics.BotCommand(command)
+		handler(m)
+	})
 }
 
-// Проверка, является ли пользователь админом
-func (b *Bot) isAdmin(userID int) bool {
-	_, exists := b.admins[userID]
-	return exists
+// Регистрация обработчика inline-кнопки
+func (b *Bot) handleButton(unique string, handler func(c *telebot.Callback)) {
+	b.bot.Handle(&telebot.InlineButton{Unique: unique}, func(c *telebot.Callback) {
+		if !b.handlers.enter() {
+			return
+		}
+		defer b.handlers.leave()
+		handler(c)
+	})
 }
 
 // Запуск бота
 func (b *Bot) Start() {
-	// Команда для генерации ссылки
-	b.bot.Handle("/register", func(m *telebot.Message) {
-		if !b.isAdmin(m.Sender.ID) {
+	// Команда для генерации ссылки (/register [кампания] [срок действия] [qr], например /register opening 48h qr)
+	b.handle("/register", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermIssueLinks) {
 			slog.Error("Попытка генерации токена, лицом без доступа", "ID", m.Sender.ID)
-			b.bot.Send(m.Sender, "У вас нет прав для использования этой команды.")
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
 			return
 		}
 
-		link, err := b.svc.GenerateUniqueLink(b.baseURL)
+		opts := domain.LinkOptions{IssuedBy: m.Sender.ID, IssuedByUsername: m.Sender.Username}
+		var withQR bool
+		for _, arg := range strings.Fields(m.Payload) {
+			if strings.EqualFold(arg, "qr") {
+				withQR = true
+				continue
+			}
+
+			// Все, что не является сроком действия, считаем кодом кампании
+			d, err := time.ParseDuration(arg)
+			if err != nil {
+				opts.CampaignCode = arg
+				continue
+			}
+			if d <= 0 {
+				b.bot.Send(m.Sender, lang.T("bot.register.bad_ttl"))
+				return
+			}
+			opts.TTL = d
+		}
+
+		link, err := b.svc.GenerateUniqueLink(b.baseURL, opts)
+		if errors.Is(err, domain.ErrCampaignNotFound) {
+			b.bot.Send(m.Sender, lang.T("bot.campaign.not_found_code", opts.CampaignCode))
+			return
+		}
 		if err != nil {
 			slog.Error("Ошибка при создании ссылки", "error", err)
-			b.bot.Send(m.Sender, "Ошибка при создании ссылки")
+			b.bot.Send(m.Sender, lang.T("bot.register.error"))
+			return
+		}
+
+		if !withQR {
+			b.bot.Send(m.Sender, lang.T("bot.register.link", link))
+			return
+		}
+
+		if err := b.sendQR(m.Sender, link); err != nil {
+			slog.Error("Ошибка при отправке QR-кода", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.register.qr_failed", link))
+		}
+	})
+
+	// Команда для генерации пачки ссылок (/register_batch N [кампания] [метка])
+	b.handle("/register_batch", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermIssueLinks) {
+			slog.Error("Попытка генерации пачки токенов, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		countStr, rest, _ := strings.Cut(strings.TrimSpace(m.Payload), " ")
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 1 || count > domain.MaxLinkBatchSize {
+			b.bot.Send(m.Sender, lang.T("bot.batch.usage", domain.MaxLinkBatchSize))
+			return
+		}
+
+		// Первое слово после количества — код кампании, если такая кампания есть,
+		// остальное — метка пачки
+		opts := domain.LinkOptions{IssuedBy: m.Sender.ID, IssuedByUsername: m.Sender.Username}
+		first, afterFirst, _ := strings.Cut(strings.TrimSpace(rest), " ")
+		_, err = b.svc.GetCampaignByCode(first)
+		switch {
+		case err == nil:
+			opts.CampaignCode = first
+			rest = afterFirst
+		case !errors.Is(err, domain.ErrCampaignNotFound):
+			slog.Error("Ошибка при получении кампании", "code", first, "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.batch.error"))
 			return
 		}
-		b.bot.Send(m.Sender, "Ваша ссылка: "+link)
+		label := strings.TrimSpace(rest)
+		opts.BatchLabel = label
+
+		links, err := b.svc.GenerateLinkBatch(b.baseURL, count, opts)
+		if err != nil {
+			slog.Error("Ошибка при создании пачки ссылок", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.batch.error"))
+			return
+		}
+
+		data, err := linksCSV(links)
+		if err != nil {
+			slog.Error("Ошибка при формировании CSV", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.batch.file_error"))
+			return
+		}
+
+		fileName := "links-" + time.Now().Format("2006-01-02-150405") + ".csv"
+		caption := lang.T("bot.batch.created", len(links))
+		if label != "" {
+			caption += "\n" + lang.T("bot.batch.label", label)
+		}
+		if err := b.sendDocument(m.Sender, fileName, data, caption); err != nil {
+			slog.Error("Ошибка при отправке файла со ссылками", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.batch.send_error"))
+		}
 	})
 
-	// Команда для проверки данных по токену
-	b.bot.Handle("/check_token", func(m *telebot.Message) {
-		if !b.isAdmin(m.Sender.ID) {
+	// Список кампаний
+	b.handle("/campaigns", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermUseBot) {
+			slog.Error("Попытка получения списка кампаний, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		campaigns, err := b.svc.ListCampaigns()
+		if err != nil {
+			slog.Error("Ошибка при получении списка кампаний", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.campaigns.error"))
+			return
+		}
+
+		response := lang.T("bot.campaigns.title") + "\n"
+		for _, c := range campaigns {
+			response += lang.T("bot.campaigns.item", c.Code, c.BonusAmount, c.ClientGroupID)
+			if campaignLang, ok := i18n.Parse(c.Language); ok {
+				response += lang.T("bot.campaigns.language", campaignLang.Name())
+			}
+			response += "\n"
+		}
+
+		b.bot.Send(m.Sender, response)
+	})
+
+	// Создание кампании (/add_campaign код бонусы группа [текст страницы успеха])
+	b.handle("/add_campaign", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageCampaigns) {
+			slog.Error("Попытка создания кампании, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		usage := lang.T("bot.add_campaign.usage")
+		args := strings.SplitN(strings.TrimSpace(m.Payload), " ", 4)
+		if len(args) < 3 {
+			b.bot.Send(m.Sender, usage)
+			return
+		}
+
+		bonus, errBonus := strconv.Atoi(args[1])
+		group, errGroup := strconv.Atoi(args[2])
+		if errBonus != nil || errGroup != nil || bonus <= 0 || group <= 0 {
+			b.bot.Send(m.Sender, usage)
+			return
+		}
+
+		campaign := &domain.Campaign{Code: args[0], BonusAmount: bonus, ClientGroupID: group}
+		if len(args) == 4 {
+			campaign.SuccessText = strings.TrimSpace(args[3])
+		}
+
+		err := b.svc.CreateCampaign(campaign)
+		if errors.Is(err, domain.ErrCampaignExists) {
+			b.bot.Send(m.Sender, lang.T("bot.add_campaign.exists"))
+			return
+		}
+		if err != nil {
+			slog.Error("Ошибка при создании кампании", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.add_campaign.error"))
+			return
+		}
+
+		b.bot.Send(m.Sender, lang.T("bot.add_campaign.created", campaign.Code))
+	})
+
+	// Язык страниц регистрации кампании (/campaign_language код ru|kk|en|-)
+	b.handle("/campaign_language", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageCampaigns) {
+			slog.Error("Попытка изменения языка кампании, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		args := strings.Fields(m.Payload)
+		if len(args) != 2 {
+			b.bot.Send(m.Sender, lang.T("bot.campaign_language.usage"))
+			return
+		}
+
+		// «-» сбрасывает язык кампании, страницы снова показываются на языке браузера
+		var campaignLang i18n.Lang
+		if args[1] != "-" {
+			parsed, ok := i18n.Parse(args[1])
+			if !ok {
+				b.bot.Send(m.Sender, lang.T("bot.campaign_language.usage"))
+				return
+			}
+			campaignLang = parsed
+		}
+
+		err := b.svc.SetCampaignLanguage(args[0], string(campaignLang))
+		if errors.Is(err, domain.ErrCampaignNotFound) {
+			b.bot.Send(m.Sender, lang.T("bot.campaign.not_found_code", args[0]))
+			return
+		}
+		if err != nil {
+			slog.Error("Ошибка при изменении языка кампании", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.campaign_language.error"))
+			return
+		}
+
+		slog.Info("Язык кампании изменён", "campaign", args[0], "language", campaignLang, "adminID", m.Sender.ID)
+		if campaignLang == "" {
+			b.bot.Send(m.Sender, lang.T("bot.campaign_language.reset", args[0]))
+			return
+		}
+		b.bot.Send(m.Sender, lang.T("bot.campaign_language.set", args[0], campaignLang.Name()))
+	})
+
+	// Отзыв ссылки (/revoke токен [причина])
+	b.handle("/revoke", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermRevokeLinks) {
+			slog.Error("Попытка отзыва токена, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		token, reason, _ := strings.Cut(strings.TrimSpace(m.Payload), " ")
+		if token == "" {
+			b.bot.Send(m.Sender, lang.T("bot.revoke.usage"))
+			return
+		}
+
+		err := b.svc.RevokeToken(token, strings.TrimSpace(reason), m.Sender.ID)
+		switch {
+		case err == nil:
+			slog.Info("Токен отозван", "token", token, "adminID", m.Sender.ID)
+		case errors.Is(err, domain.ErrTokenNotFound), errors.Is(err, domain.ErrTokenUsed), errors.Is(err, domain.ErrTokenRevoked):
+		default:
+			slog.Error("Ошибка при отзыве токена", "error", err)
+		}
+
+		b.bot.Send(m.Sender, revokeResultMessage(lang, err))
+	})
+
+	// Список регистраций, которые не удалось довести до конца
+	b.handle("/stuck_jobs", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageJobs) {
+			slog.Error("Попытка получения списка задач, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		jobs, err := b.svc.GetStuckJobs()
+		if err != nil {
+			slog.Error("Ошибка при получении списка задач", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.jobs.error"))
+			return
+		}
+
+		if len(jobs) == 0 {
+			b.bot.Send(m.Sender, lang.T("bot.jobs.none"))
+			return
+		}
+
+		response := lang.T("bot.jobs.title") + "\n"
+		for _, j := range jobs {
+			status := lang.T("bot.jobs.retry_at", j.NextAttemptAt.Local().Format("02.01 15:04"))
+			if j.Failed {
+				status = lang.T("bot.jobs.failed")
+			}
+			response += "\n" + lang.T("bot.jobs.item", j.ID, j.Name, j.Phone, j.State, j.Attempts, status, j.LastError) + "\n"
+		}
+		response += "\n" + lang.T("bot.jobs.retry_hint")
+
+		b.bot.Send(m.Sender, response)
+	})
+
+	// Немедленный повтор зависшей регистрации (/retry_job номер)
+	b.handle("/retry_job", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageJobs) {
+			slog.Error("Попытка повтора задачи, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(m.Payload))
+		if err != nil {
+			b.bot.Send(m.Sender, lang.T("bot.retry.usage"))
+			return
+		}
+
+		err = b.svc.RetryJob(id)
+		if errors.Is(err, domain.ErrJobNotFound) {
+			b.bot.Send(m.Sender, lang.T("bot.retry.not_found"))
+			return
+		}
+		if errors.Is(err, domain.ErrJobBusy) {
+			b.bot.Send(m.Sender, lang.T("bot.retry.busy"))
+			return
+		}
+		if err != nil {
+			slog.Error("Повтор регистрации не удался", "jobID", id, "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.retry.failed", err.Error()))
+			return
+		}
+
+		b.bot.Send(m.Sender, lang.T("bot.retry.done"))
+	})
+
+	// Статистика воронки регистрации (/stats [дней])
+	b.handle("/stats", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
+			slog.Error("Попытка получения статистики, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		days := domain.DefaultStatsDays
+		if arg := strings.TrimSpace(m.Payload); arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 || n > domain.MaxStatsDays {
+				b.bot.Send(m.Sender, lang.T("bot.stats.usage", domain.MaxStatsDays))
+				return
+			}
+			days = n
+		}
+
+		stats, reasons, err := b.svc.GetFunnelStats(days)
+		if err != nil {
+			slog.Error("Ошибка при получении статистики", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.stats.error"))
+			return
+		}
+
+		b.bot.Send(m.Sender, formatStats(lang, days, stats, reasons))
+	})
+
+	// Включение и отключение уведомлений о новых регистрациях
+	setNotifications := func(m *telebot.Message, enabled bool) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
+			slog.Error("Попытка изменения уведомлений, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		if err := b.svc.SetNotifications(m.Sender.ID, enabled); err != nil {
+			slog.Error("Ошибка при изменении настроек уведомлений", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.notify.error"))
+			return
+		}
+
+		response := lang.T("bot.notify.off")
+		if enabled {
+			response = lang.T("bot.notify.on")
+		}
+		if b.notifyChatID != 0 {
+			response += "\n" + lang.T("bot.notify.shared_chat")
+		}
+		b.bot.Send(m.Sender, response)
+	}
+	b.handle("/notify_on", func(m *telebot.Message) { setNotifications(m, true) })
+	b.handle("/notify_off", func(m *telebot.Message) { setNotifications(m, false) })
+
+	// Язык ответов бота (/language [ru|kk|en])
+	b.handle("/language", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermUseBot) {
+			slog.Error("Попытка изменения языка, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		// Без аргумента или с неизвестным языком показываем текущий язык и подсказку
+		chosen, ok := i18n.Parse(m.Payload)
+		if !ok {
+			b.bot.Send(m.Sender, lang.T("bot.language.current", lang.Name()))
+			return
+		}
+
+		if err := b.svc.SetAdminLanguage(m.Sender.ID, string(chosen)); err != nil {
+			slog.Error("Ошибка при изменении языка", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.language.error"))
+			return
+		}
+
+		b.bot.Send(m.Sender, chosen.T("bot.language.set"))
+	})
+
+	// Сведения о токене для администратора
+	sendTokenDetails := func(to telebot.Recipient, lang i18n.Lang, token string) {
+		details, err := b.svc.GetTokenDetails(token)
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			b.bot.Send(to, lang.T("bot.token.not_found"))
+			return
+		}
+		if err != nil {
+			slog.Error("Ошибка при поиске данных по токену", "error", err)
+			b.bot.Send(to, lang.T("bot.token.lookup_error"))
+			return
+		}
+
+		b.bot.Send(to, formatTokenDetails(lang, details))
+	}
+
+	// Команда для проверки данных по токену (/check_token [токен])
+	b.handle("/check_token", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
 			slog.Error("Попытка првоерки токена, лицом без доступа", "ID", m.Sender.ID)
-			b.bot.Send(m.Sender, "У вас нет прав для использования этой команды.")
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
 			return
 		}
 
-		b.bot.Send(m.Sender, "Введите токен для проверки:")
+		if token := strings.TrimSpace(m.Payload); token != "" {
+			b.convs.clear(m.Chat.ID)
+			sendTokenDetails(m.Sender, lang, token)
+			return
+		}
+
+		b.convs.set(m.Chat.ID, stateAwaitToken, nil)
+		b.bot.Send(m.Sender, lang.T("bot.check_token.prompt"))
 	})
 
-	// Обработчик сообщений (проверяем, ввел ли пользователь токен после команды)
+	// Отмена текущего многошагового действия
+	b.handle("/cancel", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermUseBot) {
+			return
+		}
+
+		if !b.convs.clear(m.Chat.ID) {
+			b.bot.Send(m.Sender, lang.T("bot.cancel.nothing"))
+			return
+		}
+		b.bot.Send(m.Sender, lang.T("bot.cancel.done"))
+	})
+
+	// Обработчики шагов диалога: текст администратора — ответ на вопрос бота
+	steps := map[convState]func(m *telebot.Message, conv conversation){
+		stateAwaitToken: func(m *telebot.Message, conv conversation) {
+			b.convs.clear(m.Chat.ID)
+			sendTokenDetails(m.Sender, b.language(m.Sender.ID), strings.TrimSpace(m.Text))
+		},
+	}
+
+	// Обработчик сообщений: свободный текст учитывается только на шаге диалога
 	b.bot.Handle(telebot.OnText, func(m *telebot.Message) {
-		if !b.isAdmin(m.Sender.ID) {
+		if !b.handlers.enter() {
+			return
+		}
+		defer b.handlers.leave()
+
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermUseBot) {
 			return
 		}
 
-		// Ищем данные по введенному токену
-		usage, err := b.svc.GetTokenUsage(m.Text)
+		conv, ok, expired := b.convs.get(m.Chat.ID)
+		switch {
+		case expired:
+			b.bot.Send(m.Sender, lang.T("bot.conversation.expired"))
+			return
+		case !ok:
+			b.bot.Send(m.Sender, lang.T("bot.conversation.unknown"))
+			return
+		}
+
+		step, found := steps[conv.State]
+		if !found {
+			slog.Error("Неизвестный шаг диалога", "state", conv.State)
+			b.convs.clear(m.Chat.ID)
+			return
+		}
+		step(m, conv)
+	})
+
+	// Поиск регистраций по телефону или имени (/find телефон|имя)
+	b.handle("/find", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
+			slog.Error("Попытка поиска регистраций, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		query := strings.TrimSpace(m.Payload)
+		if query == "" {
+			b.bot.Send(m.Sender, lang.T("bot.find.usage"))
+			return
+		}
+
+		// Запрос без букв считаем номером телефона и ищем по нормализованному номеру
+		var matches []domain.RegistrationMatch
+		var err error
+		if strings.IndexFunc(query, unicode.IsLetter) < 0 {
+			phoneNumber, parseErr := b.phones.Normalize(query)
+			if parseErr != nil {
+				b.bot.Send(m.Sender, phoneErrorMessage(lang, parseErr))
+				return
+			}
+			matches, err = b.svc.FindRegistrationsByPhone(phoneNumber)
+		} else {
+			matches, err = b.svc.FindRegistrationsByName(query)
+		}
 		if err != nil {
-			slog.Error("Ошибка при поиске данных или токен не найден", "error", err)
-			b.bot.Send(m.Sender, "Ошибка при поиске данных или токен не найден.")
+			slog.Error("Ошибка при поиске регистраций", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.find.error"))
 			return
 		}
 
-		// Отправляем данные о пользователе
-		response := "Данные по токену:\n"
-		response += "👤 Имя: " + usage.Username + "\n"
-		response += "📞 Телефон: " + usage.Phone
+		if len(matches) == 0 {
+			b.bot.Send(m.Sender, lang.T("bot.find.none"))
+			return
+		}
 
-		b.bot.Send(m.Sender, response)
+		b.bot.Send(m.Sender, formatMatches(lang, matches))
 	})
 
-	// Получение списка использованных токенов
-	b.bot.Handle("/used_tokens", func(m *telebot.Message) {
-		if !b.isAdmin(m.Sender.ID) {
-			slog.Error("Попытка получении списка токенов, лицом без доступа", "ID", m.Sender.ID)
-			b.bot.Send(m.Sender, "У вас нет прав для использования этой команды.")
+	// Ссылки, выданные самим администратором, с их состоянием
+	b.handle("/my_links", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermIssueLinks) {
+			slog.Error("Попытка получения списка своих ссылок, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
 			return
 		}
 
-		tokens, err := b.svc.GetUsedTokens()
+		links, err := b.svc.GetTokensIssuedBy(m.Sender.ID)
 		if err != nil {
-			slog.Error("Ошибка при получении использованных токенов", "error", err)
-			b.bot.Send(m.Sender, "Ошибка при получении использованных токенов.")
+			slog.Error("Ошибка при получении списка своих ссылок", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.my_links.error"))
 			return
 		}
 
-		if len(tokens) == 0 {
-			b.bot.Send(m.Sender, "Нет использованных токенов.")
+		if len(links) == 0 {
+			b.bot.Send(m.Sender, lang.T("bot.my_links.none"))
 			return
 		}
 
-		response := "📌 *Список использованных токенов:*\n"
-		for _, t := range tokens {
-			escapedToken := strings.ReplaceAll(t.Token, "`", "\\`")
-			response += "🔹 `" + escapedToken + "`\n"
+		now := time.Now()
+		response := lang.T("bot.my_links.title") + "\n"
+		for _, l := range links {
+			escapedToken := strings.ReplaceAll(l.Token, "`", "\\`")
+			response += fmt.Sprintf("`%s` — %s, %s\n", escapedToken, lang.T(statusLabels[l.Status(now)]), l.CreatedAt.Local().Format("02.01 15:04"))
 		}
 
 		b.bot.Send(m.Sender, response, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 	})
 
-	// Получение списка неиспользованных токенов
-	b.bot.Handle("/unused_tokens", func(m *telebot.Message) {
-		if !b.isAdmin(m.Sender.ID) {
+	// Выгрузка завершенных регистраций (/export [с] [по] [кампания] [csv|xlsx])
+	b.handle("/export", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
+			slog.Error("Попытка выгрузки регистраций, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		filter, format, err := parseExportArgs(lang, m.Payload)
+		if err != nil {
+			b.bot.Send(m.Sender, lang.T("bot.export.bad_args", err.Error()))
+			return
+		}
+
+		rows, err := b.svc.ExportRegistrations(filter)
+		if errors.Is(err, domain.ErrCampaignNotFound) {
+			b.bot.Send(m.Sender, lang.T("bot.campaign.not_found"))
+			return
+		}
+		if err != nil {
+			slog.Error("Ошибка при выгрузке регистраций", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.export.error"))
+			return
+		}
+
+		if len(rows) == 0 {
+			b.bot.Send(m.Sender, lang.T("bot.export.empty"))
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := export.Write(&buf, format, rows); err != nil {
+			slog.Error("Ошибка при формировании файла выгрузки", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.export.file_error"))
+			return
+		}
+
+		fileName := export.FileName(format, time.Now())
+		if err := b.sendDocument(m.Sender, fileName, buf.Bytes(), exportCaption(lang, filter, len(rows))); err != nil {
+			slog.Error("Ошибка при отправке файла выгрузки", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.export.send_error"))
+		}
+	})
+
+	// Список администраторов
+	b.handle("/admins", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageAdmins) {
+			slog.Error("Попытка получения списка администраторов, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		admins, err := b.svc.ListAdmins()
+		if err != nil {
+			slog.Error("Ошибка при получении списка администраторов", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.admins.error"))
+			return
+		}
+
+		b.bot.Send(m.Sender, formatAdmins(lang, admins))
+	})
+
+	// Добавление администратора или смена роли (/add_admin ID роль [имя])
+	b.handle("/add_admin", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageAdmins) {
+			slog.Error("Попытка добавления администратора, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		id, username, role, err := parseAddAdminArgs(m)
+		if err != nil {
+			b.bot.Send(m.Sender, lang.T("bot.add_admin.usage"))
+			return
+		}
+
+		err = b.svc.AddAdmin(id, username, role, m.Sender.ID)
+		if errors.Is(err, domain.ErrLastOwner) {
+			b.bot.Send(m.Sender, lang.T("bot.add_admin.last_owner"))
+			return
+		}
+		if err != nil {
+			slog.Error("Ошибка при добавлении администратора", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.add_admin.error"))
+			return
+		}
+
+		slog.Info("Администратор добавлен", "ID", id, "role", role, "addedBy", m.Sender.ID)
+		b.bot.Send(m.Sender, lang.T("bot.add_admin.done", issuerName(lang, id, username), lang.T(roleLabels[role])))
+	})
+
+	// Удаление администратора (/remove_admin ID)
+	b.handle("/remove_admin", func(m *telebot.Message) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermManageAdmins) {
+			slog.Error("Попытка удаления администратора, лицом без доступа", "ID", m.Sender.ID)
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
+			return
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(m.Payload))
+		if err != nil {
+			b.bot.Send(m.Sender, lang.T("bot.remove_admin.usage"))
+			return
+		}
+
+		err = b.svc.RemoveAdmin(id)
+		switch {
+		case errors.Is(err, domain.ErrAdminNotFound):
+			b.bot.Send(m.Sender, lang.T("bot.remove_admin.not_found"))
+		case errors.Is(err, domain.ErrLastOwner):
+			b.bot.Send(m.Sender, lang.T("bot.remove_admin.last_owner"))
+		case err != nil:
+			slog.Error("Ошибка при удалении администратора", "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.remove_admin.error"))
+		default:
+			slog.Info("Администратор удалён", "ID", id, "removedBy", m.Sender.ID)
+			b.convs.clear(int64(id))
+			b.bot.Send(m.Sender, lang.T("bot.remove_admin.done"))
+		}
+	})
+
+	// Списки токенов по страницам
+	sendTokensPage := func(m *telebot.Message, used bool) {
+		lang := b.language(m.Sender.ID)
+		if !b.can(m.Sender.ID, domain.PermViewRegistrations) {
 			slog.Error("Попытка получении списка токенов, лицом без доступа", "ID", m.Sender.ID)
-			b.bot.Send(m.Sender, "У вас нет прав для использования этой команды.")
+			b.bot.Send(m.Sender, lang.T("bot.no_access"))
 			return
 		}
 
-		tokens, err := b.svc.GetUnusedTokens()
+		page, err := b.svc.GetTokensPage(used, 1)
 		if err != nil {
-			slog.Error("Ошибка при получении неиспользованных токенов", "error", err)
-			b.bot.Send(m.Sender, "Ошибка при получении неиспользованных токенов.")
+			slog.Error("Ошибка при получении списка токенов", "used", used, "error", err)
+			b.bot.Send(m.Sender, lang.T("bot.tokens.error"))
 			return
 		}
 
-		if len(tokens) == 0 {
-			b.bot.Send(m.Sender, "Нет неиспользованных токенов.")
+		if page.Total == 0 {
+			if used {
+				b.bot.Send(m.Sender, lang.T("bot.tokens.no_used"))
+			} else {
+				b.bot.Send(m.Sender, lang.T("bot.tokens.no_unused"))
+			}
 			return
 		}
 
-		response := "📌 *Список неиспользованных токенов:*\n"
-		for _, t := range tokens {
-			escapedToken := strings.ReplaceAll(t.Token, "`", "\\`")
-			response += "🟢 `" + escapedToken + "`\n"
+		text, opts := tokenListPage(lang, page, used)
+		b.bot.Send(m.Sender, text, opts)
+	}
+	b.handle("/used_tokens", func(m *telebot.Message) { sendTokensPage(m, true) })
+	b.handle("/unused_tokens", func(m *telebot.Message) { sendTokensPage(m, false) })
+
+	// Перелистывание списка токенов
+	b.handleButton(btnTokensPage, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		if !b.can(c.Sender.ID, domain.PermViewRegistrations) {
+			b.bot.Respond(c, &telebot.CallbackResponse{Text: lang.T("bot.callback.no_access")})
+			return
 		}
 
-		b.bot.Send(m.Sender, response, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		used, pageNum, ok := parsePageData(c.Data)
+		if !ok {
+			b.bot.Respond(c)
+			return
+		}
+
+		page, err := b.svc.GetTokensPage(used, pageNum)
+		if err != nil {
+			slog.Error("Ошибка при получении списка токенов", "used", used, "error", err)
+			b.bot.Respond(c, &telebot.CallbackResponse{Text: lang.T("bot.tokens.error")})
+			return
+		}
+
+		text, opts := tokenListPage(lang, page, used)
+		if _, err := b.bot.Edit(c.Message, text, opts); err != nil {
+			slog.Warn("Не удалось обновить список токенов", "error", err)
+		}
+		b.bot.Respond(c)
+	})
+
+	// Токен из списка по идентификатору записи в данных кнопки
+	callbackToken := func(c *telebot.Callback, lang i18n.Lang, perm domain.Permission) (*domain.Registration, bool) {
+		if !b.can(c.Sender.ID, perm) {
+			b.bot.Respond(c, &telebot.CallbackResponse{Text: lang.T("bot.callback.no_access")})
+			return nil, false
+		}
+
+		id, err := strconv.Atoi(c.Data)
+		if err != nil {
+			b.bot.Respond(c)
+			return nil, false
+		}
+
+		reg, err := b.svc.GetRegistration(id)
+		if err != nil {
+			slog.Error("Ошибка при получении токена", "id", id, "error", err)
+			b.bot.Respond(c, &telebot.CallbackResponse{Text: lang.T("bot.token.not_found")})
+			return nil, false
+		}
+		return reg, true
+	}
+
+	// Сведения о токене из списка
+	b.handleButton(btnTokenInfo, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		reg, ok := callbackToken(c, lang, domain.PermViewRegistrations)
+		if !ok {
+			return
+		}
+
+		b.bot.Respond(c)
+		sendTokenDetails(c.Sender, lang, reg.Token)
+	})
+
+	// Повторная отправка QR-кода действующей ссылки
+	b.handleButton(btnTokenQR, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		reg, ok := callbackToken(c, lang, domain.PermIssueLinks)
+		if !ok {
+			return
+		}
+
+		link, err := b.svc.LinkForToken(b.baseURL, reg.Token)
+		if err != nil {
+			b.bot.Respond(c, &telebot.CallbackResponse{Text: tokenErrorMessage(lang, err), ShowAlert: true})
+			return
+		}
+
+		b.bot.Respond(c)
+		if err := b.sendQR(c.Sender, link); err != nil {
+			slog.Error("Ошибка при отправке QR-кода", "error", err)
+			b.bot.Send(c.Sender, lang.T("bot.qr.failed", link))
+		}
+	})
+
+	// Отзыв токена из списка: сначала подтверждение
+	b.handleButton(btnTokenRevoke, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		reg, ok := callbackToken(c, lang, domain.PermRevokeLinks)
+		if !ok {
+			return
+		}
+
+		b.bot.Respond(c)
+		b.bot.Send(c.Sender, lang.T("bot.revoke.confirm", reg.Token), revokeConfirmMarkup(lang, reg.ID))
+	})
+
+	b.handleButton(btnRevokeConfirm, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		reg, ok := callbackToken(c, lang, domain.PermRevokeLinks)
+		if !ok {
+			return
+		}
+
+		err := b.svc.RevokeToken(reg.Token, "", c.Sender.ID)
+		if err == nil {
+			slog.Info("Токен отозван", "token", reg.Token, "adminID", c.Sender.ID)
+		} else if !errors.Is(err, domain.ErrTokenUsed) && !errors.Is(err, domain.ErrTokenRevoked) {
+			slog.Error("Ошибка при отзыве токена", "error", err)
+		}
+
+		b.bot.Respond(c)
+		b.bot.Edit(c.Message, lang.T("bot.revoke.result", reg.Token, revokeResultMessage(lang, err)))
+	})
+
+	b.handleButton(btnRevokeCancel, func(c *telebot.Callback) {
+		lang := b.language(c.Sender.ID)
+		b.bot.Respond(c)
+		b.bot.Edit(c.Message, lang.T("bot.revoke.cancelled"))
 	})
 
+	b.setupUpdates()
 	log.Println("Бот запущен!")
+	b.running.Store(true)
+	defer b.running.Store(false)
 	b.bot.Start()
 }
+
+// Остановка получения обновлений. Уже начатые обработчики команд дорабатывают в фоне,
+// дождаться их можно через Wait.
+func (b *Bot) Stop() {
+	b.bot.Stop()
+}
+
+// Ожидание начатых обработчиков команд и кнопок. Обновления, пришедшие после
+// вызова, не обрабатываются.
+func (b *Bot) Wait(ctx context.Context) error {
+	return b.handlers.wait(ctx)
+}
+
+// Проверка готовности: бот запущен и Telegram принимает его токен
+func (b *Bot) Check(ctx context.Context) error {
+	if !b.running.Load() {
+		return errors.New("bot is not running")
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- callAPI(b.bot, "getMe", map[string]any{}) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Настройка получения обновлений в Telegram. Если webhook зарегистрировать
+// не удалось, бот переходит на long polling.
+func (b *Bot) setupUpdates() {
+	if b.webhook != nil {
+		err := b.webhook.register(b.bot)
+		if err == nil {
+			slog.Info("Бот получает обновления через webhook", "path", b.webhook.Path())
+			return
+		}
+		slog.Error("Не удалось зарегистрировать webhook, используется long polling", "error", err)
+		b.bot.Poller = &telebot.LongPoller{Timeout: 10 * time.Second}
+	}
+
+	// Пока в Telegram зарегистрирован webhook, getUpdates не работает
+	if err := callAPI(b.bot, "deleteWebhook", map[string]any{}); err != nil {
+		slog.Error("Не удалось удалить webhook", "error", err)
+	}
+}
+
+// Ответ администратору на попытку отзыва токена
+func revokeResultMessage(lang i18n.Lang, err error) string {
+	switch {
+	case err == nil:
+		return lang.T("bot.revoke.done")
+	case errors.Is(err, domain.ErrTokenNotFound):
+		return lang.T("bot.token.not_found")
+	case errors.Is(err, domain.ErrTokenUsed):
+		return lang.T("bot.revoke.used")
+	case errors.Is(err, domain.ErrTokenRevoked):
+		return lang.T("bot.revoke.already")
+	default:
+		return lang.T("bot.revoke.error")
+	}
+}
+
+// Отправка файла документом
+func (b *Bot) sendDocument(to telebot.Recipient, fileName string, data []byte, caption string) error {
+	return withTempFile(fileName, data, func(path string) error {
+		doc := &telebot.Document{File: telebot.FromDisk(path), FileName: fileName, Caption: caption}
+		_, err := b.bot.Send(to, doc)
+		return err
+	})
+}
+
+// Отправка QR-кода ссылки картинкой, сама ссылка идет подписью
+func (b *Bot) sendQR(to telebot.Recipient, link string) error {
+	png, err := qrPNG(link)
+	if err != nil {
+		return err
+	}
+
+	return withTempFile("qr.png", png, func(path string) error {
+		photo := &telebot.Photo{File: telebot.FromDisk(path), Caption: link}
+		_, err := b.bot.Send(to, photo)
+		return err
+	})
+}
+
+// Telebot умеет загружать только файлы с диска, поэтому содержимое
+// сначала сохраняется во временный каталог и удаляется после отправки
+func withTempFile(fileName string, data []byte, send func(path string) error) error {
+	dir, err := os.MkdirTemp("", "links-bot-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+
+	return send(path)
+}
+
+// Список ссылок в формате CSV
+func linksCSV(links []domain.Link) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{"token", "link", "expires_at"}); err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		expiresAt := ""
+		if !l.ExpiresAt.IsZero() {
+			expiresAt = l.ExpiresAt.Format("2006-01-02 15:04")
+		}
+		if err := w.Write([]string{l.Token, l.URL, expiresAt}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,110 @@
+package delivery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tucnak/telebot"
+)
+
+// Ответы Telegram без сети: getMe при создании бота, остальные вызовы — пустой успех
+type fakeTelegram struct{}
+
+func (fakeTelegram) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"ok":true,"result":true}`
+	if strings.HasSuffix(r.URL.Path, "/getMe") {
+		body = `{"ok":true,"result":{"id":1,"first_name":"Test","username":"test_bot"}}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+// Поставщик обновлений, который отдает заранее заданные обновления
+type fakePoller struct {
+	updates []telebot.Update
+}
+
+func (p *fakePoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	for _, upd := range p.updates {
+		dest <- upd
+	}
+	<-stop
+	close(stop)
+}
+
+func newTestBot(t *testing.T, updates ...telebot.Update) *Bot {
+	t.Helper()
+	transport := http.DefaultTransport
+	http.DefaultTransport = fakeTelegram{}
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	tb, err := telebot.NewBot(telebot.Settings{Token: "test", Poller: &fakePoller{updates: updates}})
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	return &Bot{bot: tb}
+}
+
+func TestHandleButton(t *testing.T) {
+	b := newTestBot(t, telebot.Update{Callback: &telebot.Callback{ID: "1", Data: "\f" + btnTokenInfo + "|42"}})
+
+	called := make(chan string, 1)
+	release := make(chan struct{})
+	b.handleButton(btnTokenInfo, func(c *telebot.Callback) {
+		called <- c.Data
+		<-release
+	})
+
+	go b.bot.Start()
+	defer b.bot.Stop()
+
+	select {
+	case data := <-called:
+		if data != "42" {
+			t.Errorf("callback data = %q, want %q", data, "42")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("button handler was not called")
+	}
+
+	// Начатый обработчик учитывается при остановке
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); err == nil {
+		t.Error("Wait returned before the handler finished")
+	}
+
+	close(release)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Errorf("Wait: %v", err)
+	}
+}
+
+func TestHandleSkipsUpdatesAfterWait(t *testing.T) {
+	b := newTestBot(t, telebot.Update{Message: &telebot.Message{Text: "/stats", Sender: &telebot.User{ID: 1}, Chat: &telebot.Chat{ID: 1}}})
+
+	called := make(chan struct{}, 1)
+	b.handle("/stats", func(m *telebot.Message) {
+		called <- struct{}{}
+	})
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	go b.bot.Start()
+	defer b.bot.Stop()
+
+	select {
+	case <-called:
+		t.Error("handler ran after Wait")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
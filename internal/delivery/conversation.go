@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"sync"
+	"time"
+)
+
+// Сколько бот ждет ответа администратора на шаге диалога
+const conversationTimeout = 5 * time.Minute
+
+// Шаг многошагового диалога с администратором
+type convState string
+
+const (
+	stateAwaitToken convState = "await_token" // /check_token ждет токен
+)
+
+// Состояние диалога в одном чате. Data хранит значения, собранные на предыдущих шагах.
+type conversation struct {
+	State     convState
+	Data      map[string]string
+	ExpiresAt time.Time
+}
+
+// conversations — состояния диалогов по чатам. Свободный текст обрабатывается
+// только если чат находится на каком-то шаге диалога.
+type conversations struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	byChat  map[int64]conversation
+}
+
+func newConversations(timeout time.Duration) *conversations {
+	return &conversations{timeout: timeout, byChat: make(map[int64]conversation)}
+}
+
+// Перевод чата на шаг диалога, срок ожидания отсчитывается заново
+func (c *conversations) set(chatID int64, state convState, data map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.byChat[chatID] = conversation{State: state, Data: data, ExpiresAt: time.Now().Add(c.timeout)}
+}
+
+// Текущий шаг диалога. expired сообщает, что шаг был, но время ожидания истекло.
+func (c *conversations) get(chatID int64) (conv conversation, ok, expired bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conv, ok = c.byChat[chatID]
+	if !ok {
+		return conversation{}, false, false
+	}
+	if time.Now().After(conv.ExpiresAt) {
+		delete(c.byChat, chatID)
+		return conversation{}, false, true
+	}
+	return conv, true, false
+}
+
+// Завершение диалога. Возвращает false, если диалога не было.
+func (c *conversations) clear(chatID int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.byChat[chatID]
+	delete(c.byChat, chatID)
+	return ok
+}
@@ -0,0 +1,67 @@
+package delivery
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"certificate/internal/domain"
+	"certificate/internal/export"
+	"certificate/internal/i18n"
+)
+
+// Формат дат в аргументах /export
+const exportDateLayout = "2006-01-02"
+
+// Разбор аргументов /export [с] [по] [кампания] [csv|xlsx]: первая дата — начало
+// периода, вторая — конец, csv или xlsx — формат, остальное — код кампании.
+// Тексты ошибок на языке lang, они показываются администратору.
+func parseExportArgs(lang i18n.Lang, payload string) (domain.ExportFilter, export.Format, error) {
+	var filter domain.ExportFilter
+	format := export.FormatCSV
+
+	for _, arg := range strings.Fields(payload) {
+		if day, err := time.ParseInLocation(exportDateLayout, arg, time.Local); err == nil {
+			switch {
+			case filter.From.IsZero():
+				filter.From = day
+			case filter.To.IsZero():
+				filter.To = day
+			default:
+				return filter, format, errors.New(lang.T("export.extra_date", arg))
+			}
+			continue
+		}
+
+		if f, err := export.ParseFormat(arg); err == nil {
+			format = f
+			continue
+		}
+
+		if filter.CampaignCode != "" {
+			return filter, format, errors.New(lang.T("export.unknown_arg", arg))
+		}
+		filter.CampaignCode = arg
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, format, errors.New(lang.T("export.bad_period"))
+	}
+
+	return filter, format, nil
+}
+
+// Подпись к файлу выгрузки
+func exportCaption(lang i18n.Lang, filter domain.ExportFilter, count int) string {
+	caption := lang.T("export.caption.count", count)
+	switch {
+	case !filter.From.IsZero() && !filter.To.IsZero():
+		caption += "\n" + lang.T("export.caption.period", filter.From.Format(exportDateLayout), filter.To.Format(exportDateLayout))
+	case !filter.From.IsZero():
+		caption += "\n" + lang.T("export.caption.since", filter.From.Format(exportDateLayout))
+	}
+	if filter.CampaignCode != "" {
+		caption += "\n" + lang.T("export.caption.campaign", filter.CampaignCode)
+	}
+	return caption
+}
@@ -0,0 +1,127 @@
+package delivery
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+	"certificate/internal/phone"
+)
+
+const (
+	minNameLength = 2
+	maxNameLength = 100
+	// Старше этого возраста дата рождения считается опечаткой
+	maxAge = 120
+	// Формат значения поля <input type="date">
+	birthdayLayout = "2006-01-02"
+)
+
+// Данные формы регистрации. При ошибках форма показывается заново
+// с введенными значениями и сообщением под каждым неверным полем.
+type registrationForm struct {
+	Token    string
+	Name     string
+	Phone    string
+	Birthday string
+	Errors   map[string]string
+}
+
+func parseRegistrationForm(values url.Values) *registrationForm {
+	get := func(key string) string {
+		return strings.TrimSpace(values.Get(key))
+	}
+	return &registrationForm{
+		Token:    get("token"),
+		Name:     strings.Join(strings.Fields(get("name")), " "),
+		Phone:    get("phone"),
+		Birthday: get("birthday"),
+		Errors:   make(map[string]string),
+	}
+}
+
+// Проверка всех полей формы с сообщениями на языке lang. Возвращает номер
+// телефона в формате E.164, если ошибок нет.
+func (f *registrationForm) validate(lang i18n.Lang, phones *phone.Parser, minAge int, now time.Time) string {
+	if msg := validateName(lang, f.Name); msg != "" {
+		f.Errors["name"] = msg
+	}
+	if msg := validateBirthday(lang, f.Birthday, minAge, now); msg != "" {
+		f.Errors["birthday"] = msg
+	}
+
+	if f.Phone == "" {
+		f.Errors["phone"] = lang.T("form.phone.required")
+		return ""
+	}
+	phoneNumber, err := phones.Normalize(f.Phone)
+	if err != nil {
+		f.Errors["phone"] = phoneErrorMessage(lang, err)
+		return ""
+	}
+	return phoneNumber
+}
+
+func (f *registrationForm) valid() bool {
+	return len(f.Errors) == 0
+}
+
+func validateName(lang i18n.Lang, name string) string {
+	if name == "" {
+		return lang.T("form.name.required")
+	}
+	length := utf8.RuneCountInString(name)
+	if length < minNameLength || length > maxNameLength {
+		return lang.T("form.name.length", minNameLength, maxNameLength)
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && r != ' ' && r != '-' && r != '\'' && r != '.' {
+			return lang.T("form.name.chars")
+		}
+	}
+	return ""
+}
+
+func validateBirthday(lang i18n.Lang, value string, minAge int, now time.Time) string {
+	if value == "" {
+		return lang.T("form.birthday.required")
+	}
+	birthday, err := time.Parse(birthdayLayout, value)
+	if err != nil {
+		return lang.T("form.birthday.format")
+	}
+
+	age := ageAt(birthday, now)
+	switch {
+	case birthday.After(now):
+		return lang.T("form.birthday.future")
+	case age > maxAge:
+		return lang.T("form.birthday.too_old")
+	case age < minAge:
+		return lang.N("form.birthday.min_age", minAge)
+	}
+	return ""
+}
+
+// Полное количество лет на дату now
+func ageAt(birthday, now time.Time) int {
+	age := now.Year() - birthday.Year()
+	if now.Month() < birthday.Month() || (now.Month() == birthday.Month() && now.Day() < birthday.Day()) {
+		age--
+	}
+	return age
+}
+
+// Текст ошибки для некорректного номера телефона
+func phoneErrorMessage(lang i18n.Lang, err error) string {
+	if errors.Is(err, domain.ErrUnknownOperator) {
+		return lang.T("form.phone.operator")
+	}
+	return lang.T("form.phone.format")
+}
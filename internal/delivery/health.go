@@ -0,0 +1,100 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Сколько ждать ответа одной проверки
+	healthCheckTimeout = 5 * time.Second
+	// Сколько хранить результат проверок внешних сервисов, чтобы частые
+	// запросы /readyz не упирались в лимиты Poster и Telegram
+	externalCheckTTL = 30 * time.Second
+)
+
+// Проверка зависимости для /healthz и /readyz
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// Подключение дополнительной проверки готовности, например бота.
+// Результат кэшируется на externalCheckTTL.
+func (s *HTTPServer) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	s.readiness = append(s.readiness, healthCheck{name: name, check: cachedCheck(externalCheckTTL, check)})
+}
+
+// Живость: процесс отвечает и БД доступна на чтение
+func (s *HTTPServer) HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	runChecks(w, r, []healthCheck{{name: "database", check: s.svc.CheckDatabase}})
+}
+
+// Готовность: БД, Poster и подключенные проверки
+func (s *HTTPServer) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	runChecks(w, r, s.readiness)
+}
+
+// Выполнение проверок и ответ в JSON: 200, если все прошли, иначе 503
+func runChecks(w http.ResponseWriter, r *http.Request, checks []healthCheck) {
+	results := make(map[string]string, len(checks))
+	status := http.StatusOK
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+			defer cancel()
+
+			// Адреса открыты без авторизации, поэтому подробности ошибки только в логе:
+			// в них могут оказаться адреса запросов к внешним сервисам
+			result := "ok"
+			if err := c.check(ctx); err != nil {
+				slog.Warn("Проверка не прошла", "check", c.name, "error", err)
+				result = "fail"
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[c.name] = result
+			if result != "ok" {
+				status = http.StatusServiceUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": http.StatusText(status),
+		"checks": results,
+	})
+}
+
+// Проверка, результат которой переиспользуется в течение ttl
+func cachedCheck(ttl time.Duration, check func(ctx context.Context) error) func(ctx context.Context) error {
+	var mu sync.Mutex
+	var checkedAt time.Time
+	var last error
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return last
+		}
+		last = check(ctx)
+		checkedAt = time.Now()
+		return last
+	}
+}
@@ -0,0 +1,40 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunChecksHidesErrors(t *testing.T) {
+	checks := []healthCheck{
+		{name: "database", check: func(ctx context.Context) error { return nil }},
+		{name: "poster", check: func(ctx context.Context) error {
+			return errors.New(`Get "https://joinposter.com/api/clients.getGroups?token=SECRET": timeout`)
+		}},
+	}
+
+	w := httptest.NewRecorder()
+	runChecks(w, httptest.NewRequest("GET", "/readyz", nil), checks)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if strings.Contains(w.Body.String(), "SECRET") {
+		t.Errorf("response contains error details: %s", w.Body)
+	}
+
+	var resp struct {
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Checks["database"] != "ok" || resp.Checks["poster"] != "fail" {
+		t.Errorf("checks = %v, want database ok, poster fail", resp.Checks)
+	}
+}
@@ -1,37 +1,103 @@
 package delivery
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"time"
 
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+	"certificate/internal/metrics"
+	"certificate/internal/phone"
 	"certificate/internal/ports"
 )
 
 type HTTPServer struct {
-	svc ports.RegistrationService
+	svc     ports.RegistrationService
+	baseURL string
+	phones  *phone.Parser
+	// Минимальный возраст для регистрации, 0 — без ограничения
+	minAge int
+	mux    *http.ServeMux
+	server *http.Server
+	// Проверки для /readyz
+	readiness []healthCheck
+	// Начатые запросы
+	requests inflight
 }
 
-func NewHTTPServer(svc ports.RegistrationService) *HTTPServer {
-	return &HTTPServer{svc: svc}
+func NewHTTPServer(svc ports.RegistrationService, baseURL string, phones *phone.Parser, minAge int) *HTTPServer {
+	s := &HTTPServer{svc: svc, baseURL: baseURL, phones: phones, minAge: minAge, mux: http.NewServeMux()}
+	s.server = &http.Server{Handler: s.track(s.mux), ReadHeaderTimeout: 10 * time.Second}
+	s.mux.HandleFunc("GET /register", s.HandleRegister)
+	s.mux.HandleFunc("POST /submit", s.HandleSubmit)
+	s.mux.HandleFunc("GET /qr", s.HandleQR)
+	s.mux.HandleFunc("GET /healthz", s.HandleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.HandleReadyz)
+	s.mux.Handle("GET /metrics", metrics.Handler())
+
+	s.readiness = []healthCheck{
+		{name: "database", check: svc.CheckDatabase},
+		{name: "poster", check: cachedCheck(externalCheckTTL, svc.CheckPoster)},
+	}
+	return s
+}
+
+// Запуск сервера. Возвращает nil после остановки через Shutdown.
+func (s *HTTPServer) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Запуск HTTP-сервера на", addr)
+	if err := s.server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-// Запуск сервера
-func (s *HTTPServer) Start(port string) {
-	http.HandleFunc("GET /register", s.HandleRegister)
-	http.HandleFunc("POST /submit", s.HandleSubmit)
+// Остановка сервера: новые соединения не принимаются, начатые запросы
+// (в том числе /submit с вызовами Poster) дорабатывают до отмены контекста
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Ожидание запросов, которые еще выполняются после Shutdown (если он не дождался их
+// до отмены контекста). Новые запросы после вызова отклоняются.
+func (s *HTTPServer) Wait(ctx context.Context) error {
+	return s.requests.wait(ctx)
+}
 
-	log.Println("Запуск HTTP-сервера на", port)
-	log.Fatal(http.ListenAndServe(port, nil))
+// Учет начатых запросов, чтобы не закрыть БД, пока они работают
+func (s *HTTPServer) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.requests.enter() {
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		defer s.requests.leave()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Подключение приема обновлений Telegram
+func (s *HTTPServer) HandleWebhook(w *Webhook) {
+	s.mux.Handle("POST "+w.Path(), w)
 }
 
 // Загрузка статических файлов
 func (s *HTTPServer) ServeStaticFiles() {
 	fs := http.FileServer(http.Dir("templates/styles"))
-	http.Handle("/styles/", http.StripPrefix("/styles/", fs))
+	s.mux.Handle("/styles/", http.StripPrefix("/styles/", fs))
 	ft := http.FileServer(http.Dir("templates/fonts"))
-	http.Handle("/fonts/", http.StripPrefix("/fonts/", ft))
+	s.mux.Handle("/fonts/", http.StripPrefix("/fonts/", ft))
 }
 
 // Обработчик регистрации(когда перешли по ссылке)
@@ -44,42 +110,145 @@ func (s *HTTPServer) HandleRegister(w http.ResponseWriter, r *http.Request) {
 
 	token, err := s.svc.ValidateAndDecode(encryptedToken)
 	if err != nil {
-		s.renderPage(w, "error.html", map[string]string{"Message": "Invalid token"})
+		lang := s.pageLanguage(r, "")
+		s.renderPage(w, lang, "error.html", map[string]string{"Message": tokenErrorMessage(lang, err)})
 		return
 	}
+	s.svc.TrackLinkOpened(token)
 
-	s.renderPage(w, "register.html", map[string]string{"Token": token})
+	// В форму передаем зашифрованный токен: при отправке он проверяется заново.
+	// Телефон проверяется только на сервере по настройкам PHONE_COUNTRIES.
+	form := &registrationForm{Token: encryptedToken, Phone: s.phones.Prefix()}
+	s.renderPage(w, s.pageLanguage(r, token), "register.html", form)
 }
 
-// Вспомогательный метод для рендера HTML-шаблонов
-func (s *HTTPServer) renderPage(w http.ResponseWriter, templateName string, data map[string]string) {
-	tmpl, err := template.ParseFiles("templates/" + templateName)
+// QR-код ссылки для показа на планшете (только для действующего неиспользованного токена)
+func (s *HTTPServer) HandleQR(w http.ResponseWriter, r *http.Request) {
+	encryptedToken := r.URL.Query().Get("token")
+	if encryptedToken == "" {
+		http.Error(w, "Token is missing", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.svc.ValidateAndDecode(encryptedToken); err != nil {
+		http.Error(w, tokenErrorMessage(s.pageLanguage(r, ""), err), http.StatusNotFound)
+		return
+	}
+
+	png, err := qrPNG(s.baseURL + encryptedToken)
+	if err != nil {
+		slog.Error("Ошибка генерации QR-кода", "error", err)
+		http.Error(w, "Failed to generate QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(png)
+}
+
+// Вспомогательный метод для рендера HTML-шаблонов. В шаблонах доступны
+// функции t (сообщение каталога), lang (код языка страницы) и languages
+// (поддерживаемые языки для переключателя).
+func (s *HTTPServer) renderPage(w http.ResponseWriter, lang i18n.Lang, templateName string, data any) {
+	funcs := template.FuncMap{
+		"t":         lang.T,
+		"lang":      func() i18n.Lang { return lang },
+		"languages": func() []i18n.Lang { return i18n.Supported },
+	}
+
+	tmpl, err := template.New(templateName).Funcs(funcs).ParseFiles("templates/" + templateName)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error loading template: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Content-Language", string(lang))
 	tmpl.Execute(w, data)
 }
 
 // Обработчик регистрации(после того, как нажали сабмит)
 func (s *HTTPServer) HandleSubmit(w http.ResponseWriter, r *http.Request) {
-	token := r.FormValue("token")
-	name := r.FormValue("name")
-	phone := r.FormValue("phone")
-	birthday := r.FormValue("birthday")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
 
-	if token == "" || name == "" || phone == "" || birthday == "" {
-		http.Error(w, "Missing required fields", http.StatusBadRequest)
+	form := parseRegistrationForm(r.PostForm)
+	if form.Token == "" {
+		http.Error(w, "Token is missing", http.StatusBadRequest)
 		return
 	}
 
-	err := s.svc.RegisterUser(token, name, phone, birthday)
+	token, err := s.svc.ValidateAndDecode(form.Token)
+	if err != nil {
+		lang := s.pageLanguage(r, "")
+		s.renderPage(w, lang, "error.html", map[string]string{"Message": tokenErrorMessage(lang, err)})
+		return
+	}
+	lang := s.pageLanguage(r, token)
+
+	// В Poster и в записи об использовании токена номер попадает в формате E.164
+	phoneNumber := form.validate(lang, s.phones, s.minAge, time.Now())
+	if !form.valid() {
+		s.renderPage(w, lang, "register.html", form)
+		return
+	}
+
+	campaign, err := s.svc.RegisterUser(token, form.Name, phoneNumber, form.Birthday)
+	if errors.Is(err, domain.ErrTokenUsed) || errors.Is(err, domain.ErrTokenRevoked) {
+		s.renderPage(w, lang, "error.html", map[string]string{"Message": tokenErrorMessage(lang, err)})
+		return
+	}
+	if errors.Is(err, domain.ErrPosterValidation) {
+		// Токен освобожден, поэтому клиент может исправить данные прямо в форме
+		slog.Warn("Poster отклонил данные клиента", "error", err)
+		form.Errors["form"] = lang.T("web.register.rejected")
+		s.renderPage(w, lang, "register.html", form)
+		return
+	}
+	if errors.Is(err, domain.ErrRegistrationPending) {
+		// Данные приняты, но бонусы еще не начислены: текст кампании о начислении не показываем
+		s.renderPage(w, lang, "success.html", map[string]any{"Pending": true})
+		return
+	}
 	if err != nil {
+		slog.Error("Ошибка регистрации", "error", err)
 		http.Error(w, "Registration failed", http.StatusBadRequest)
 		return
 	}
 
-	s.renderPage(w, "success.html", map[string]string{"Message": "Registration successful!"})
+	s.renderPage(w, lang, "success.html", map[string]any{"Message": successMessage(lang, campaign)})
+}
+
+// Текст кампании после регистрации. Он хранится на одном языке (языке кампании,
+// по умолчанию русском), поэтому на странице на другом языке вместо него
+// показывается сообщение из каталога с суммой бонусов.
+func successMessage(lang i18n.Lang, campaign *domain.Campaign) string {
+	textLang, ok := i18n.Parse(campaign.Language)
+	if !ok {
+		textLang = i18n.Default
+	}
+	if campaign.SuccessText != "" && textLang == lang {
+		return campaign.SuccessText
+	}
+	if campaign.BonusAmount > 0 {
+		return lang.N("web.success.bonus", campaign.BonusAmount)
+	}
+	return ""
+}
+
+// Текст ошибки для страницы в зависимости от причины отказа
+func tokenErrorMessage(lang i18n.Lang, err error) string {
+	switch {
+	case errors.Is(err, domain.ErrTokenExpired):
+		return lang.T("token.expired")
+	case errors.Is(err, domain.ErrTokenUsed):
+		return lang.T("token.used")
+	case errors.Is(err, domain.ErrTokenRevoked):
+		return lang.T("token.revoked")
+	default:
+		return lang.T("token.invalid")
+	}
 }
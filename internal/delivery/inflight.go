@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"context"
+	"sync"
+)
+
+// Учет начатых обработчиков, чтобы при остановке дождаться их до закрытия БД
+type inflight struct {
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// Отметка о начале обработки. false — идет остановка, новую обработку начинать нельзя.
+func (f *inflight) enter() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return false
+	}
+	f.wg.Add(1)
+	return true
+}
+
+func (f *inflight) leave() {
+	f.wg.Done()
+}
+
+// Ожидание начатых обработчиков. После вызова новые обработчики не запускаются.
+func (f *inflight) wait(ctx context.Context) error {
+	f.mu.Lock()
+	f.stopped = true
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
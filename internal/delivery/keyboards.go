@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+
+	"github.com/tucnak/telebot"
+)
+
+// Идентификаторы кнопок, по ним telebot находит обработчик нажатия
+const (
+	btnTokensPage    = "tokens_page"    // данные: вид списка|страница
+	btnTokenInfo     = "token_info"     // данные: id записи
+	btnTokenRevoke   = "token_revoke"   // данные: id записи
+	btnRevokeConfirm = "revoke_confirm" // данные: id записи
+	btnRevokeCancel  = "revoke_cancel"
+	btnTokenQR       = "token_qr" // данные: id записи
+)
+
+// Вид списка токенов в данных кнопок
+const (
+	listUsed   = "u"
+	listUnused = "n"
+)
+
+// Текст и клавиатура страницы списка токенов
+func tokenListPage(lang i18n.Lang, p *domain.TokenPage, used bool) (string, *telebot.SendOptions) {
+	kind, title := listUnused, lang.T("tokens.title.unused")
+	if used {
+		kind, title = listUsed, lang.T("tokens.title.used")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lang.T("tokens.page", title, p.Total, p.Page, p.Pages) + "\n")
+
+	now := time.Now()
+	var keyboard [][]telebot.InlineButton
+	for i, t := range p.Tokens {
+		n := (p.Page-1)*domain.TokensPageSize + i + 1
+		escapedToken := strings.ReplaceAll(t.Token, "`", "\\`")
+		fmt.Fprintf(&sb, "%d. `%s` — %s\n", n, escapedToken, lang.T(statusLabels[t.Status(now)]))
+
+		id := strconv.Itoa(t.ID)
+		row := []telebot.InlineButton{{Unique: btnTokenInfo, Text: fmt.Sprintf("ℹ️ %d", n), Data: id}}
+		if !used {
+			row = append(row,
+				telebot.InlineButton{Unique: btnTokenQR, Text: "📱 QR", Data: id},
+				telebot.InlineButton{Unique: btnTokenRevoke, Text: lang.T("tokens.button.revoke"), Data: id},
+			)
+		}
+		keyboard = append(keyboard, row)
+	}
+
+	var nav []telebot.InlineButton
+	if p.Page > 1 {
+		nav = append(nav, telebot.InlineButton{Unique: btnTokensPage, Text: lang.T("tokens.button.prev"), Data: kind + "|" + strconv.Itoa(p.Page-1)})
+	}
+	if p.Page < p.Pages {
+		nav = append(nav, telebot.InlineButton{Unique: btnTokensPage, Text: lang.T("tokens.button.next"), Data: kind + "|" + strconv.Itoa(p.Page+1)})
+	}
+	if len(nav) > 0 {
+		keyboard = append(keyboard, nav)
+	}
+
+	return sb.String(), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: keyboard},
+	}
+}
+
+// Разбор данных кнопки перелистывания: вид списка и номер страницы
+func parsePageData(data string) (used bool, page int, ok bool) {
+	kind, pageStr, found := strings.Cut(data, "|")
+	page, err := strconv.Atoi(pageStr)
+	if !found || err != nil || (kind != listUsed && kind != listUnused) {
+		return false, 0, false
+	}
+	return kind == listUsed, page, true
+}
+
+// Клавиатура подтверждения отзыва токена
+func revokeConfirmMarkup(lang i18n.Lang, id int) *telebot.SendOptions {
+	return &telebot.SendOptions{ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: [][]telebot.InlineButton{{
+		{Unique: btnRevokeConfirm, Text: lang.T("tokens.button.revoke_confirm"), Data: strconv.Itoa(id)},
+		{Unique: btnRevokeCancel, Text: lang.T("tokens.button.cancel")},
+	}}}}
+}
@@ -0,0 +1,47 @@
+package delivery
+
+import (
+	"log/slog"
+	"net/http"
+
+	"certificate/internal/i18n"
+)
+
+// Язык страницы регистрации: явный выбор клиента (параметр lang в ссылке
+// или скрытое поле формы), язык браузера, язык кампании токена, иначе язык
+// по умолчанию. Язык кампании — только значение по умолчанию для браузеров,
+// которые не прислали ни одного поддерживаемого языка.
+// token — расшифрованный токен или пустая строка, если он недействителен.
+func (s *HTTPServer) pageLanguage(r *http.Request, token string) i18n.Lang {
+	if lang, ok := i18n.Parse(r.FormValue("lang")); ok {
+		return lang
+	}
+
+	if lang, ok := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return lang
+	}
+
+	if token != "" {
+		campaign, err := s.svc.GetTokenCampaign(token)
+		if err != nil {
+			slog.Warn("Не удалось получить кампанию токена", "error", err)
+		} else if lang, ok := i18n.Parse(campaign.Language); ok {
+			return lang
+		}
+	}
+	return i18n.Default
+}
+
+// Язык ответов бота, выбранный администратором командой /language,
+// иначе язык по умолчанию. Telebot v2 не передает язык интерфейса Telegram,
+// поэтому выбор только явный.
+func (b *Bot) language(userID int) i18n.Lang {
+	saved, err := b.svc.AdminLanguage(userID)
+	if err != nil {
+		slog.Error("Ошибка при получении языка администратора", "ID", userID, "error", err)
+	}
+	if lang, ok := i18n.Parse(saved); ok {
+		return lang
+	}
+	return i18n.Default
+}
@@ -0,0 +1,67 @@
+package delivery
+
+import (
+	"log/slog"
+	"strings"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+	"certificate/internal/phone"
+
+	"github.com/tucnak/telebot"
+)
+
+// Уведомление о завершенной регистрации. Если задан чат для уведомлений,
+// сообщение уходит только туда на языке по умолчанию, иначе — каждому администратору
+// с доступом к регистрациям, который их не отключил, на его языке.
+func (b *Bot) NotifyRegistration(n domain.RegistrationNotice) {
+	if b.notifyChatID != 0 {
+		if _, err := b.bot.Send(&telebot.Chat{ID: b.notifyChatID}, formatNotice(i18n.Default, n)); err != nil {
+			slog.Error("Ошибка при отправке уведомления в чат", "chatID", b.notifyChatID, "error", err)
+		}
+		return
+	}
+
+	admins, err := b.svc.ListAdmins()
+	if err != nil {
+		slog.Error("Ошибка при получении списка администраторов", "error", err)
+		return
+	}
+
+	for _, admin := range admins {
+		if !admin.Role.Can(domain.PermViewRegistrations) {
+			continue
+		}
+
+		id := admin.ID
+		enabled, err := b.svc.NotificationsEnabled(id)
+		if err != nil {
+			slog.Error("Ошибка при получении настроек уведомлений", "adminID", id, "error", err)
+			continue
+		}
+		if !enabled {
+			continue
+		}
+
+		if _, err := b.bot.Send(&telebot.User{ID: id}, formatNotice(b.language(id), n)); err != nil {
+			slog.Error("Ошибка при отправке уведомления", "adminID", id, "error", err)
+		}
+	}
+}
+
+func formatNotice(lang i18n.Lang, n domain.RegistrationNotice) string {
+	client := lang.T("notice.client_new")
+	if n.ClientExisted {
+		client = lang.T("notice.client_existed")
+	}
+
+	lines := []string{
+		lang.T("notice.title"),
+		lang.T("details.name", n.Name),
+		lang.T("details.phone", phone.Mask(n.Phone)),
+		lang.T("notice.client", n.ClientID, client),
+		lang.T("details.campaign", n.CampaignCode),
+		lang.T("notice.issued_by", issuerName(lang, n.IssuedBy, n.IssuedByUsername)),
+	}
+	return strings.Join(lines, "\n")
+}
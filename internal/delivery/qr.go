@@ -0,0 +1,11 @@
+package delivery
+
+import qrcode "github.com/skip2/go-qrcode"
+
+// Размер стороны QR-кода в пикселях
+const qrSize = 512
+
+// PNG с QR-кодом для ссылки
+func qrPNG(link string) ([]byte, error) {
+	return qrcode.Encode(link, qrcode.Medium, qrSize)
+}
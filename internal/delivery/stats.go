@@ -0,0 +1,99 @@
+package delivery
+
+import (
+	"fmt"
+	"strings"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+)
+
+// Этапы воронки в порядке прохождения и ключи каталога с их подписями
+var funnelSteps = []struct {
+	event domain.EventType
+	label string
+}{
+	{domain.EventLinkGenerated, "stats.step.issued"},
+	{domain.EventLinkOpened, "stats.step.opened"},
+	{domain.EventFormSubmitted, "stats.step.submitted"},
+	{domain.EventClientCreated, "stats.step.created"},
+	{domain.EventClientFound, "stats.step.found"},
+	{domain.EventBonusAwarded, "stats.step.awarded"},
+	{domain.EventRegistrationFailed, "stats.step.failed"},
+}
+
+// Ключи каталога с подписями кодов причин неудачных регистраций
+var failureReasonLabels = map[string]string{
+	"poster_auth":        "stats.reason.poster_auth",
+	"poster_validation":  "stats.reason.poster_validation",
+	"poster_rate_limit":  "stats.reason.poster_rate_limit",
+	"poster_unavailable": "stats.reason.poster_unavailable",
+	"internal":           "stats.reason.internal",
+}
+
+// Текст ответа /stats: воронка по дням, по кампаниям и частые причины неудач
+func formatStats(lang i18n.Lang, days int, stats []domain.FunnelStats, reasons []domain.ReasonCount) string {
+	if len(stats) == 0 {
+		return lang.N("stats.empty", days)
+	}
+
+	var byDay, byCampaign []domain.FunnelStats
+	for _, row := range stats {
+		byDay = mergeStats(byDay, row, func(s domain.FunnelStats) bool { return s.Day == row.Day })
+		byCampaign = mergeStats(byCampaign, row, func(s domain.FunnelStats) bool { return s.CampaignID == row.CampaignID })
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\n%s\n", lang.N("stats.title", days), lang.T("stats.by_day"))
+	for _, s := range byDay {
+		fmt.Fprintf(&sb, "📅 %s: %s\n", s.Day, funnelLine(lang, s.Counts))
+	}
+
+	sb.WriteString("\n" + lang.T("stats.by_campaign") + "\n")
+	for _, s := range byCampaign {
+		code := s.CampaignCode
+		if code == "" {
+			code = lang.T("stats.no_campaign")
+		}
+		fmt.Fprintf(&sb, "🔸 %s: %s", code, funnelLine(lang, s.Counts))
+		if generated := s.Counts[domain.EventLinkGenerated]; generated > 0 {
+			fmt.Fprintf(&sb, " (%s)", lang.T("stats.conversion", s.Counts[domain.EventBonusAwarded]*100/generated))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(reasons) > 0 {
+		sb.WriteString("\n" + lang.T("stats.reasons") + "\n")
+		for _, r := range reasons {
+			label := r.Reason
+			if key, ok := failureReasonLabels[r.Reason]; ok {
+				label = lang.T(key)
+			}
+			fmt.Fprintf(&sb, "• %s — %d\n", label, r.Count)
+		}
+	}
+
+	return sb.String()
+}
+
+// Добавление строки к первой группе, подходящей под same, или новая группа
+func mergeStats(groups []domain.FunnelStats, row domain.FunnelStats, same func(domain.FunnelStats) bool) []domain.FunnelStats {
+	for i := range groups {
+		if same(groups[i]) {
+			groups[i].Add(row)
+			return groups
+		}
+	}
+
+	group := domain.FunnelStats{Day: row.Day, CampaignID: row.CampaignID, CampaignCode: row.CampaignCode}
+	group.Add(row)
+	return append(groups, group)
+}
+
+func funnelLine(lang i18n.Lang, counts map[domain.EventType]int) string {
+	parts := make([]string, 0, len(funnelSteps))
+	for _, step := range funnelSteps {
+		parts = append(parts, fmt.Sprintf("%s %d", lang.T(step.label), counts[step.event]))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"certificate/internal/domain"
+	"certificate/internal/i18n"
+)
+
+// Ключи каталога с подписями состояний ссылки
+var statusLabels = map[domain.RegistrationStatus]string{
+	domain.StatusActive:  "status.active",
+	domain.StatusUsed:    "status.used",
+	domain.StatusRevoked: "status.revoked",
+	domain.StatusExpired: "status.expired",
+}
+
+// Кто выдал ссылку: @username (ID) или только ID
+func issuerName(lang i18n.Lang, id int, username string) string {
+	switch {
+	case id == 0:
+		return lang.T("issuer.unknown")
+	case username != "":
+		return "@" + username + " (ID " + strconv.Itoa(id) + ")"
+	default:
+		return "ID " + strconv.Itoa(id)
+	}
+}
+
+// Текст ответа /check_token
+func formatTokenDetails(lang i18n.Lang, d *domain.TokenDetails) string {
+	reg := d.Registration
+
+	var sb strings.Builder
+	sb.WriteString(lang.T("details.title") + "\n")
+	sb.WriteString(lang.T("details.status", lang.T(statusLabels[reg.Status(time.Now())])) + "\n")
+	if d.CampaignCode != "" {
+		sb.WriteString(lang.T("details.campaign", d.CampaignCode) + "\n")
+	}
+	if reg.BatchLabel != "" {
+		sb.WriteString(lang.T("details.label", reg.BatchLabel) + "\n")
+	}
+	sb.WriteString(lang.T("details.issued_by", issuerName(lang, reg.IssuedBy, reg.IssuedByUsername)) + "\n")
+	if !reg.CreatedAt.IsZero() {
+		sb.WriteString(lang.T("details.issued_at", reg.CreatedAt.Local().Format("02.01.2006 15:04")) + "\n")
+	}
+	if !reg.ExpiresAt.IsZero() {
+		sb.WriteString(lang.T("details.expires_at", reg.ExpiresAt.Local().Format("02.01.2006 15:04")) + "\n")
+	}
+	if reg.Revoked {
+		sb.WriteString(lang.T("details.revoked_by", reg.RevokedBy))
+		if reg.RevokedReason != "" {
+			sb.WriteString(lang.T("details.revoked_reason", reg.RevokedReason))
+		}
+		sb.WriteString("\n")
+	}
+
+	switch {
+	case d.Usage != nil:
+		sb.WriteString(lang.T("details.name", d.Usage.Username) + "\n")
+		sb.WriteString(lang.T("details.phone", d.Usage.Phone) + "\n")
+		if d.Usage.ClientID != 0 {
+			sb.WriteString(lang.T("details.client", d.Usage.ClientID) + "\n")
+		}
+	case reg.Used:
+		sb.WriteString(lang.T("details.pending") + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// Текст ответа /find
+func formatMatches(lang i18n.Lang, matches []domain.RegistrationMatch) string {
+	var sb strings.Builder
+	sb.WriteString(lang.T("matches.found", len(matches)))
+	if len(matches) == domain.MaxSearchResults {
+		fmt.Fprintf(&sb, " (%s)", lang.T("matches.truncated"))
+	}
+	sb.WriteString("\n")
+
+	for _, m := range matches {
+		sb.WriteString("\n👤 " + m.Usage.Username + ", " + m.Usage.Phone + "\n")
+		sb.WriteString(lang.T("matches.token", m.Usage.Token) + "\n")
+		if !m.Usage.CreatedAt.IsZero() {
+			sb.WriteString(lang.T("matches.date", m.Usage.CreatedAt.Local().Format("02.01.2006 15:04")) + "\n")
+		}
+		if m.Usage.ClientID != 0 {
+			sb.WriteString(lang.T("details.client", m.Usage.ClientID) + "\n")
+		}
+		sb.WriteString(lang.T("matches.issued_by", issuerName(lang, m.IssuedBy, m.IssuedByUsername)) + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
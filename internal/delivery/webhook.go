@@ -0,0 +1,110 @@
+package delivery
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tucnak/telebot"
+)
+
+// Заголовок, в котором Telegram присылает секрет, заданный при setWebhook
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Максимальный размер тела запроса с обновлением
+const maxWebhookBody = 1 << 20
+
+// Webhook принимает обновления Telegram через HTTP-сервер вместо long polling.
+// Реализует telebot.Poller для бота и http.Handler для сервера.
+type Webhook struct {
+	url     string
+	path    string
+	secret  string
+	updates chan telebot.Update
+}
+
+func NewWebhook(webhookURL, secret string) (*Webhook, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Path == "" || u.Path == "/" {
+		return nil, fmt.Errorf("webhook url must have a path")
+	}
+	return &Webhook{url: webhookURL, path: u.Path, secret: secret, updates: make(chan telebot.Update, 100)}, nil
+}
+
+// Путь, по которому обработчик подключается к HTTP-серверу
+func (w *Webhook) Path() string {
+	return w.path
+}
+
+// Передача принятых обновлений боту до его остановки
+func (w *Webhook) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	for {
+		select {
+		case upd := <-w.updates:
+			dest <- upd
+		case <-stop:
+			close(stop)
+			return
+		}
+	}
+}
+
+// Прием обновления от Telegram. Запросы без верного секрета отклоняются.
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(webhookSecretHeader)), []byte(w.secret)) != 1 {
+		slog.Warn("Запрос к webhook с неверным секретом", "remote", r.RemoteAddr)
+		http.Error(rw, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var upd telebot.Update
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxWebhookBody)).Decode(&upd); err != nil {
+		slog.Warn("Некорректное обновление от Telegram", "error", err)
+		http.Error(rw, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case w.updates <- upd:
+		rw.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// Telegram повторит доставку, если не дождется ответа
+	}
+}
+
+// Регистрация webhook в Telegram
+func (w *Webhook) register(b *telebot.Bot) error {
+	return callAPI(b, "setWebhook", map[string]any{
+		"url":             w.url,
+		"secret_token":    w.secret,
+		"allowed_updates": []string{"message", "callback_query"},
+	})
+}
+
+// Вызов метода Bot API, ответ которого не нужен, кроме признака успеха
+func callAPI(b *telebot.Bot, method string, payload any) error {
+	data, err := b.Raw(method, payload)
+	if err != nil {
+		// Ошибка транспорта содержит адрес запроса вместе с токеном бота
+		return fmt.Errorf("%s: %s", method, strings.ReplaceAll(err.Error(), b.Token, "***"))
+	}
+
+	var resp struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("%s: bad response: %w", method, err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("%s: %s", method, resp.Description)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Роль администратора бота
+type AdminRole string
+
+const (
+	// Полный доступ, включая управление администраторами
+	RoleOwner AdminRole = "owner"
+	// Всё, кроме управления администраторами
+	RoleManager AdminRole = "manager"
+	// Только выдача ссылок
+	RoleIssuer AdminRole = "issuer"
+	// Только просмотр токенов, регистраций и статистики
+	RoleReadOnly AdminRole = "readonly"
+)
+
+// Действие в боте, на которое нужно право
+type Permission int
+
+const (
+	// Базовые команды: список кампаний, отмена действия
+	PermUseBot Permission = iota
+	// Выдача ссылок и просмотр своих ссылок
+	PermIssueLinks
+	// Просмотр токенов, регистраций, статистики, выгрузка и уведомления
+	PermViewRegistrations
+	// Отзыв ссылок
+	PermRevokeLinks
+	// Создание кампаний
+	PermManageCampaigns
+	// Просмотр и повтор зависших регистраций
+	PermManageJobs
+	// Добавление и удаление администраторов
+	PermManageAdmins
+)
+
+var rolePermissions = map[AdminRole][]Permission{
+	RoleOwner: {
+		PermUseBot, PermIssueLinks, PermViewRegistrations, PermRevokeLinks,
+		PermManageCampaigns, PermManageJobs, PermManageAdmins,
+	},
+	RoleManager: {
+		PermUseBot, PermIssueLinks, PermViewRegistrations, PermRevokeLinks,
+		PermManageCampaigns, PermManageJobs,
+	},
+	RoleIssuer:   {PermUseBot, PermIssueLinks},
+	RoleReadOnly: {PermUseBot, PermViewRegistrations},
+}
+
+// Разбор названия роли
+func ParseAdminRole(s string) (AdminRole, error) {
+	role := AdminRole(s)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", fmt.Errorf("unknown admin role %q", s)
+	}
+	return role, nil
+}
+
+// Есть ли у роли право на действие
+func (r AdminRole) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Admin — пользователь Telegram с доступом к боту
+type Admin struct {
+	ID       int
+	Username string
+	Role     AdminRole
+	// Кто добавил администратора, 0 — назначен из переменной ADMINS
+	AddedBy   int
+	CreatedAt time.Time
+}
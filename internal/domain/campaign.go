@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Кампания, к которой привязаны ссылки при отсутствии явного выбора
+const DefaultCampaignID = 1
+
+// Campaign — промоакция со своим размером бонуса и группой клиентов в Poster
+type Campaign struct {
+	ID            int
+	Code          string
+	BonusAmount   int
+	ClientGroupID int
+	SuccessText   string
+	// Язык страниц регистрации по умолчанию, пустая строка — по браузеру клиента
+	Language  string
+	CreatedAt time.Time
+}
@@ -6,4 +6,5 @@ type Client struct {
 	Sex      int
 	Phone    string
 	Birthday string
+	GroupID  int
 }
@@ -0,0 +1,31 @@
+package domain
+
+import "errors"
+
+var (
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenUsed     = errors.New("token already used")
+	ErrTokenExpired  = errors.New("token expired")
+	ErrTokenExists   = errors.New("token already exists")
+	ErrTokenRevoked  = errors.New("token revoked")
+
+	ErrCampaignNotFound = errors.New("campaign not found")
+	ErrCampaignExists   = errors.New("campaign already exists")
+
+	ErrJobNotFound = errors.New("registration job not found")
+	ErrJobBusy     = errors.New("registration job is being processed")
+	// Регистрация принята, но еще не доведена до конца в Poster
+	ErrRegistrationPending = errors.New("registration is pending")
+
+	ErrAdminNotFound = errors.New("admin not found")
+	ErrLastOwner     = errors.New("cannot remove the last owner")
+
+	ErrInvalidPhone    = errors.New("invalid phone number")
+	ErrUnknownOperator = errors.New("unknown operator code")
+
+	// Виды ошибок Poster API
+	ErrPosterAuth       = errors.New("poster: authorization failed")
+	ErrPosterValidation = errors.New("poster: request rejected")
+	ErrPosterRateLimit  = errors.New("poster: rate limit exceeded")
+	ErrPosterServer     = errors.New("poster: server unavailable")
+)
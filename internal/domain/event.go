@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Этап воронки регистрации
+type EventType string
+
+const (
+	EventLinkGenerated      EventType = "link_generated"      // админ выдал ссылку
+	EventLinkOpened         EventType = "link_opened"         // клиент открыл форму по действующей ссылке
+	EventFormSubmitted      EventType = "form_submitted"      // форма прошла проверку и отправлена
+	EventClientCreated      EventType = "client_created"      // в Poster создан новый клиент
+	EventClientFound        EventType = "client_found"        // клиент уже был в Poster
+	EventBonusAwarded       EventType = "bonus_awarded"       // бонусы начислены
+	EventRegistrationFailed EventType = "registration_failed" // регистрация не удалась, причина в Reason
+)
+
+// За сколько дней показывается статистика по умолчанию и максимум
+const (
+	DefaultStatsDays = 7
+	MaxStatsDays     = 90
+)
+
+// Event — событие воронки регистрации
+type Event struct {
+	ID         int
+	Type       EventType
+	Token      string
+	CampaignID int
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// FunnelStats — количество событий каждого этапа за день по одной кампании
+type FunnelStats struct {
+	Day          string // дата в формате 2006-01-02 (UTC)
+	CampaignID   int
+	CampaignCode string
+	Counts       map[EventType]int
+}
+
+// Add суммирует счетчики другой строки статистики
+func (s *FunnelStats) Add(other FunnelStats) {
+	if s.Counts == nil {
+		s.Counts = make(map[EventType]int)
+	}
+	for t, n := range other.Counts {
+		s.Counts[t] += n
+	}
+}
+
+// ReasonCount — сколько раз регистрация не удалась по одной причине
+type ReasonCount struct {
+	Reason string
+	Count  int
+}
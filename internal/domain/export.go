@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ExportFilter — условия выгрузки регистраций. Нулевые значения — без ограничения.
+type ExportFilter struct {
+	From         time.Time // с начала этого дня
+	To           time.Time // до конца этого дня
+	CampaignCode string
+}
+
+// ExportRow — строка выгрузки: ссылка и данные клиента, который по ней зарегистрировался
+type ExportRow struct {
+	Token            string
+	CampaignCode     string
+	BatchLabel       string
+	IssuedBy         int
+	IssuedByUsername string
+	IssuedAt         time.Time
+	RegisteredAt     time.Time
+	Name             string
+	Phone            string
+	Birthday         string
+	ClientID         int
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Максимальное количество ссылок, создаваемых одной командой
+const MaxLinkBatchSize = 500
+
+// Link — сгенерированная ссылка на регистрацию
+type Link struct {
+	Token     string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// Параметры генерации ссылок
+type LinkOptions struct {
+	TTL          time.Duration // 0 — срок действия по умолчанию
+	CampaignCode string        // пустая строка — кампания по умолчанию
+	BatchLabel   string
+	IssuedBy     int // Telegram ID администратора, выдающего ссылку
+	// Имя пользователя Telegram администратора, выдающего ссылку
+	IssuedByUsername string
+}
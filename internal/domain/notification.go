@@ -0,0 +1,13 @@
+package domain
+
+// RegistrationNotice — данные о завершенной регистрации для уведомления администраторов
+type RegistrationNotice struct {
+	Token            string
+	Name             string
+	Phone            string // в формате E.164, маскируется при отправке
+	ClientID         int
+	ClientExisted    bool
+	CampaignCode     string
+	IssuedBy         int // Telegram ID администратора, выдавшего ссылку, 0 — неизвестно
+	IssuedByUsername string
+}
@@ -1,7 +1,68 @@
 package domain
 
+import "time"
+
 type Registration struct {
-	ID    int
-	Token string
-	Used  bool
+	ID         int
+	Token      string
+	Used       bool
+	CreatedAt  time.Time
+	ExpiresAt  time.Time // нулевое значение — ссылка бессрочная
+	BatchLabel string
+	CampaignID int
+	IssuedBy   int // Telegram ID администратора, выдавшего ссылку, 0 — неизвестно
+	// Имя пользователя Telegram администратора на момент выдачи ссылки
+	IssuedByUsername string
+
+	Revoked       bool
+	RevokedReason string
+	RevokedBy     int // Telegram ID администратора, отозвавшего ссылку
+	RevokedAt     time.Time
+}
+
+// Состояние ссылки
+type RegistrationStatus string
+
+const (
+	StatusActive  RegistrationStatus = "active"
+	StatusUsed    RegistrationStatus = "used"
+	StatusRevoked RegistrationStatus = "revoked"
+	StatusExpired RegistrationStatus = "expired"
+)
+
+// Состояние ссылки на момент now
+func (r *Registration) Status(now time.Time) RegistrationStatus {
+	switch {
+	case r.Used:
+		return StatusUsed
+	case r.Revoked:
+		return StatusRevoked
+	case r.Expired(now):
+		return StatusExpired
+	default:
+		return StatusActive
+	}
+}
+
+// TokenDetails — все, что известно о ссылке, для проверки администратором
+type TokenDetails struct {
+	Registration Registration
+	CampaignCode string
+	Usage        *TokenUsage // nil, пока регистрация не завершена
+}
+
+// Истёк ли срок действия ссылки на момент now
+func (r *Registration) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// Сколько токенов показывается на одной странице списка
+const TokensPageSize = 10
+
+// TokenPage — страница списка токенов
+type TokenPage struct {
+	Tokens []Registration
+	Page   int // номер страницы начиная с 1
+	Pages  int
+	Total  int
 }
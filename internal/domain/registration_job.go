@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Шаг, до которого дошла регистрация клиента в Poster
+type JobState string
+
+const (
+	JobPending       JobState = "pending"        // токен захвачен, клиент в Poster еще не создан
+	JobClientCreated JobState = "client_created" // клиент создан или найден, бонусы не начислены
+	JobBonusAwarded  JobState = "bonus_awarded"  // бонусы начислены, использование токена не записано
+	JobDone          JobState = "done"
+)
+
+// RegistrationJob — отложенная регистрация клиента в Poster (transactional outbox).
+// Создается вместе с захватом токена и доводится до конца фоновым обработчиком.
+type RegistrationJob struct {
+	ID         int
+	Token      string
+	Name       string
+	Phone      string
+	Birthday   string
+	CampaignID int
+
+	State    JobState
+	Failed   bool // обработчик исчерпал попытки, нужна помощь администратора
+	ClientID int
+	// Клиент уже был в Poster до регистрации
+	ClientExisted bool
+
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -1,8 +1,22 @@
 package domain
 
+import "time"
+
 type TokenUsage struct {
-	ID       int
-	Token    string
-	Username string
-	Phone    string
+	ID        int
+	Token     string
+	Username  string
+	Phone     string
+	ClientID  int
+	CreatedAt time.Time
+}
+
+// Сколько записей максимум возвращает поиск регистраций
+const MaxSearchResults = 20
+
+// RegistrationMatch — найденная регистрация вместе с тем, кто выдал ссылку
+type RegistrationMatch struct {
+	Usage            TokenUsage
+	IssuedBy         int
+	IssuedByUsername string
 }
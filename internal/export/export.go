@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"certificate/internal/domain"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Формат файла выгрузки
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Формат даты в ячейках выгрузки
+const timeLayout = "2006-01-02 15:04"
+
+var header = []string{
+	"token", "campaign", "batch_label", "issued_by", "issued_by_username", "issued_at",
+	"registered_at", "name", "phone", "birthday", "poster_client_id",
+}
+
+// Разбор названия формата, пустая строка — CSV
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q", s)
+	}
+}
+
+// Имя файла выгрузки, например registrations-2024-05-01-120000.xlsx
+func FileName(format Format, now time.Time) string {
+	return "registrations-" + now.Format("2006-01-02-150405") + "." + string(format)
+}
+
+// Запись выгрузки в выбранном формате
+func Write(w io.Writer, format Format, rows []domain.ExportRow) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatXLSX:
+		return writeXLSX(w, rows)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+func writeCSV(w io.Writer, rows []domain.ExportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(record(r)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeXLSX(w io.Writer, rows []domain.ExportRow) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Sheet1"
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	if err := sw.SetRow("A1", cells(header)); err != nil {
+		return err
+	}
+	for i, r := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, cells(record(r))); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// Строка выгрузки в виде текстовых ячеек
+func record(r domain.ExportRow) []string {
+	issuedBy, clientID := "", ""
+	if r.IssuedBy != 0 {
+		issuedBy = strconv.Itoa(r.IssuedBy)
+	}
+	if r.ClientID != 0 {
+		clientID = strconv.Itoa(r.ClientID)
+	}
+
+	return []string{
+		r.Token, r.CampaignCode, r.BatchLabel, issuedBy, r.IssuedByUsername, formatTime(r.IssuedAt),
+		formatTime(r.RegisteredAt), r.Name, r.Phone, r.Birthday, clientID,
+	}
+}
+
+func cells(values []string) []any {
+	row := make([]any, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return row
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(timeLayout)
+}
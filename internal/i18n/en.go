@@ -0,0 +1,226 @@
+package i18n
+
+// Английский каталог
+var en = map[string]string{
+	// Страницы регистрации
+	"web.title.register":        "Registration",
+	"web.title.success":         "Registration completed",
+	"web.title.error":           "Error",
+	"web.register.heading":      "Registration Form",
+	"web.register.name":         "Name:",
+	"web.register.phone":        "Phone:",
+	"web.register.birthday":     "Birthday:",
+	"web.register.submit":       "Submit",
+	"web.register.rejected":     "We couldn't register you with these details. Please check them and try again.",
+	"web.success.heading":       "Registration completed successfully.",
+	"web.success.visit":         "To use them, just come to our coffee shop and give your details.",
+	"web.success.wallet":        "For your convenience, you can also add a virtual bonus card to your wallet to always have access to your points and use our loyalty program.",
+	"web.success.wallet_prefix": "To do this, simply",
+	"web.success.wallet_link":   "add the card to your wallet",
+	"web.success.bonus.one":     "You now have %d bonus point in your account to spend on coffee at our coffee shop.",
+	"web.success.bonus.other":   "You now have %d bonus points in your account to spend on coffee at our coffee shop.",
+	"web.pending.heading":       "Your registration has been received.",
+	"web.pending.text":          "The bonus points will be credited shortly, there is no need to submit the form again.",
+	"web.error.heading":         "Registration error",
+	"web.error.help":            "If you are registering with it for the first time, please contact the event organizers for help.",
+
+	// Причины, по которым ссылка не действует
+	"token.expired": "This link has expired.",
+	"token.used":    "This link has already been used to register.",
+	"token.revoked": "This link was revoked by the organizers.",
+	"token.invalid": "This link is invalid.",
+
+	// Проверка полей формы
+	"form.name.required":          "Please enter your name.",
+	"form.name.length":            "The name must be %d to %d characters long.",
+	"form.name.chars":             "The name may only contain letters, spaces, hyphens, apostrophes and periods.",
+	"form.birthday.required":      "Please enter your date of birth.",
+	"form.birthday.format":        "Invalid date of birth format.",
+	"form.birthday.future":        "The date of birth can't be in the future.",
+	"form.birthday.too_old":       "Please check your year of birth.",
+	"form.birthday.min_age.one":   "Registration is available from the age of %d.",
+	"form.birthday.min_age.other": "Registration is available from the age of %d.",
+	"form.phone.required":         "Please enter your phone number.",
+	"form.phone.operator":         "Invalid operator code!",
+	"form.phone.format":           "Invalid phone number format!",
+
+	// Общие ответы бота
+	"bot.no_access":               "You don't have permission to use this command.",
+	"bot.callback.no_access":      "Access denied.",
+	"bot.campaign.not_found":      "Campaign not found. List of campaigns: /campaigns",
+	"bot.campaign.not_found_code": "Campaign \"%s\" not found. List of campaigns: /campaigns",
+	"bot.conversation.expired":    "Timed out waiting for your reply. Please repeat the command.",
+	"bot.conversation.unknown":    "I don't understand this message. Choose a command first, e.g. /check_token.",
+	"bot.cancel.nothing":          "Nothing to cancel.",
+	"bot.cancel.done":             "Action cancelled.",
+
+	// Выдача ссылок
+	"bot.register.bad_ttl":   "Invalid expiry. Example: /register 48h qr",
+	"bot.register.error":     "Failed to create the link",
+	"bot.register.link":      "Your link: %s",
+	"bot.register.qr_failed": "Couldn't send the QR code. Your link: %s",
+	"bot.batch.usage":        "Specify the number of links from 1 to %d. Example: /register_batch 50 opening launch",
+	"bot.batch.error":        "Failed to create the links",
+	"bot.batch.file_error":   "Failed to create the file with links",
+	"bot.batch.send_error":   "Failed to send the file with links",
+	"bot.batch.created":      "Links created: %d",
+	"bot.batch.label":        "Label: %s",
+	"bot.my_links.error":     "Failed to get the list of links.",
+	"bot.my_links.none":      "You haven't issued any links yet.",
+	"bot.my_links.title":     "🔗 *Your latest links:*",
+	"bot.qr.failed":          "Couldn't send the QR code. Link: %s",
+
+	// Кампании
+	"bot.campaigns.error":         "Failed to get the list of campaigns.",
+	"bot.campaigns.title":         "📣 Campaigns:",
+	"bot.campaigns.item":          "🔸 %s — %d bonus points, group %d",
+	"bot.campaigns.language":      ", pages language: %s",
+	"bot.add_campaign.usage":      "Format: /add_campaign code bonus group [success page text]\nExample: /add_campaign partner 500 3 You've got 500 bonus points!",
+	"bot.add_campaign.exists":     "A campaign with this code already exists.",
+	"bot.add_campaign.error":      "Failed to create the campaign.",
+	"bot.add_campaign.created":    "Campaign \"%s\" created.",
+	"bot.campaign_language.usage": "Format: /campaign_language code ru|kk|en|-\nThe language is used when the client's browser reports none of the supported languages; \"-\" means Russian.\nExample: /campaign_language opening kk",
+	"bot.campaign_language.error": "Failed to change the campaign language.",
+	"bot.campaign_language.set":   "If the client's browser reports no supported language, registration pages of campaign \"%s\" are shown in: %s.",
+	"bot.campaign_language.reset": "The language of campaign \"%s\" was reset: pages follow the client's browser language, otherwise Russian.",
+
+	// Отзыв и проверка токенов
+	"bot.revoke.usage":       "Format: /revoke token [reason]",
+	"bot.revoke.done":        "The token is revoked, the link no longer works.",
+	"bot.revoke.used":        "The token has already been used and can't be revoked.",
+	"bot.revoke.already":     "The token has already been revoked.",
+	"bot.revoke.error":       "Failed to revoke the token.",
+	"bot.revoke.confirm":     "Revoke token %s? The link will stop working.",
+	"bot.revoke.result":      "Token %s: %s",
+	"bot.revoke.cancelled":   "Revocation cancelled.",
+	"bot.token.not_found":    "Token not found.",
+	"bot.token.lookup_error": "Failed to look up the token.",
+	"bot.check_token.prompt": "Enter the token to check (or /cancel to cancel):",
+	"bot.tokens.error":       "Failed to get the list of tokens.",
+	"bot.tokens.no_used":     "No used tokens.",
+	"bot.tokens.no_unused":   "No unused tokens.",
+
+	// Списки токенов и кнопки
+	"tokens.title.unused":          "🟢 *Unused tokens*",
+	"tokens.title.used":            "📌 *Used tokens*",
+	"tokens.page":                  "%s (%d), page %d of %d:",
+	"tokens.button.revoke":         "🚫 Revoke",
+	"tokens.button.prev":           "◀️ Back",
+	"tokens.button.next":           "Next ▶️",
+	"tokens.button.revoke_confirm": "🚫 Yes, revoke",
+	"tokens.button.cancel":         "Cancel",
+	"status.active":                "🟢 active",
+	"status.used":                  "✅ used",
+	"status.revoked":               "🚫 revoked",
+	"status.expired":               "⌛ expired",
+
+	// Сведения о токенах и регистрациях
+	"issuer.unknown":         "unknown",
+	"details.title":          "Token details:",
+	"details.status":         "Status: %s",
+	"details.campaign":       "📣 Campaign: %s",
+	"details.label":          "📦 Label: %s",
+	"details.issued_by":      "🔑 Issued by: %s",
+	"details.issued_at":      "🕒 Issued at: %s",
+	"details.expires_at":     "⏳ Valid until: %s",
+	"details.revoked_by":     "🚫 Revoked by: ID %d",
+	"details.revoked_reason": ", reason: %s",
+	"details.name":           "👤 Name: %s",
+	"details.phone":          "📞 Phone: %s",
+	"details.client":         "🆔 Poster client: %d",
+	"details.pending":        "Registration isn't completed in Poster yet, see /stuck_jobs",
+	"bot.find.usage":         "Format: /find phone or /find name\nExample: /find 87771234567",
+	"bot.find.error":         "Failed to search registrations.",
+	"bot.find.none":          "No registrations found.",
+	"matches.found":          "🔎 Registrations found: %d",
+	"matches.truncated":      "showing the latest, refine your query",
+	"matches.token":          "🔑 Token: %s",
+	"matches.date":           "🕒 Date: %s",
+	"matches.issued_by":      "Issued by: %s",
+
+	// Уведомления о регистрациях
+	"bot.notify.error":       "Failed to change notification settings.",
+	"bot.notify.on":          "Notifications about new registrations are on.",
+	"bot.notify.off":         "Notifications about new registrations are off.",
+	"bot.notify.shared_chat": "Notifications currently go to the shared chat; your personal setting is saved for later.",
+	"notice.title":           "✅ New registration",
+	"notice.client":          "🆔 Poster client: %d (%s)",
+	"notice.client_new":      "new client",
+	"notice.client_existed":  "client already existed in Poster",
+	"notice.issued_by":       "🔑 Link issued by: %s",
+
+	// Зависшие регистрации
+	"bot.jobs.error":      "Failed to get the list of jobs.",
+	"bot.jobs.none":       "There are no stuck registrations.",
+	"bot.jobs.title":      "⚠️ Stuck registrations:",
+	"bot.jobs.item":       "#%d %s, %s\nStep: %s, attempts: %d (%s)\nError: %s",
+	"bot.jobs.retry_at":   "retry at %s",
+	"bot.jobs.failed":     "out of attempts",
+	"bot.jobs.retry_hint": "Retry: /retry_job number",
+	"bot.retry.usage":     "Format: /retry_job number",
+	"bot.retry.not_found": "Job not found.",
+	"bot.retry.busy":      "The job is being processed right now, try again later.",
+	"bot.retry.failed":    "Retry failed: %s",
+	"bot.retry.done":      "Registration completed.",
+
+	// Статистика
+	"bot.stats.usage":                 "Specify the number of days from 1 to %d. Example: /stats 30",
+	"bot.stats.error":                 "Failed to get statistics.",
+	"stats.empty.one":                 "No events in the last %d day.",
+	"stats.empty.other":               "No events in the last %d days.",
+	"stats.title.one":                 "📊 Statistics for %d day",
+	"stats.title.other":               "📊 Statistics for %d days",
+	"stats.by_day":                    "By day:",
+	"stats.by_campaign":               "By campaign:",
+	"stats.no_campaign":               "no campaign",
+	"stats.conversion":                "conversion %d%%",
+	"stats.reasons":                   "Failure reasons:",
+	"stats.step.issued":               "issued",
+	"stats.step.opened":               "opened",
+	"stats.step.submitted":            "submitted",
+	"stats.step.created":              "new",
+	"stats.step.found":                "found",
+	"stats.step.awarded":              "bonuses",
+	"stats.step.failed":               "errors",
+	"stats.reason.poster_auth":        "Poster rejected the access token",
+	"stats.reason.poster_validation":  "Poster rejected the client data",
+	"stats.reason.poster_rate_limit":  "Poster rate limit exceeded",
+	"stats.reason.poster_unavailable": "Poster is unavailable",
+	"stats.reason.internal":           "internal error",
+
+	// Выгрузка регистраций
+	"bot.export.bad_args":     "Error: %s.\nExample: /export 2024-05-01 2024-05-31 opening xlsx",
+	"bot.export.error":        "Failed to export registrations.",
+	"bot.export.empty":        "No registrations in the selected period.",
+	"bot.export.file_error":   "Failed to create the export file.",
+	"bot.export.send_error":   "Failed to send the export file.",
+	"export.extra_date":       "extra date %s",
+	"export.unknown_arg":      "unknown argument %s",
+	"export.bad_period":       "the end date is before the start date",
+	"export.caption.count":    "Registrations: %d",
+	"export.caption.period":   "Period: %s — %s",
+	"export.caption.since":    "Since %s",
+	"export.caption.campaign": "Campaign: %s",
+
+	// Администраторы
+	"bot.admins.error":            "Failed to get the list of admins.",
+	"admins.title":                "👥 Admins:",
+	"role.owner":                  "👑 owner",
+	"role.manager":                "🛠 manager",
+	"role.issuer":                 "🔑 link issuing",
+	"role.readonly":               "👀 read-only",
+	"bot.add_admin.usage":         "Specify the user ID and role: owner, manager, issuer or readonly. Example: /add_admin 123456789 issuer barista\nYou can also reply with /add_admin role to a forwarded message from the user.",
+	"bot.add_admin.last_owner":    "You can't demote the only owner. Appoint another owner first.",
+	"bot.add_admin.error":         "Failed to add the admin.",
+	"bot.add_admin.done":          "Admin %s: %s",
+	"bot.remove_admin.usage":      "Specify the admin ID. Example: /remove_admin 123456789",
+	"bot.remove_admin.not_found":  "Admin not found.",
+	"bot.remove_admin.last_owner": "You can't remove the only owner.",
+	"bot.remove_admin.error":      "Failed to remove the admin.",
+	"bot.remove_admin.done":       "Admin removed.",
+
+	// Язык ответов бота
+	"bot.language.current": "Bot language: %s.\nChange it: /language ru|kk|en",
+	"bot.language.error":   "Failed to change the language.",
+	"bot.language.set":     "The bot will reply in English.",
+}
@@ -0,0 +1,152 @@
+// Пакет i18n содержит каталоги сообщений на русском, казахском и английском
+// и выбор языка по настройкам пользователя и заголовку Accept-Language
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Язык интерфейса, код ISO 639-1
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	Kazakh  Lang = "kk"
+	English Lang = "en"
+)
+
+// Язык, если другой выбрать не удалось. На нем же ищется сообщение,
+// которого нет в каталоге выбранного языка.
+const Default = Russian
+
+// Поддерживаемые языки в порядке показа в переключателе
+var Supported = []Lang{Russian, Kazakh, English}
+
+var catalogs = map[Lang]map[string]string{
+	Russian: ru,
+	Kazakh:  kk,
+	English: en,
+}
+
+// Названия языков на них самих
+var names = map[Lang]string{
+	Russian: "Русский",
+	Kazakh:  "Қазақша",
+	English: "English",
+}
+
+// Разбор кода языка: "ru", "kk-KZ", "en_US". Код "kz" часто используют
+// вместо "kk", поэтому он тоже принимается.
+func Parse(s string) (Lang, bool) {
+	code := strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if code == "kz" {
+		code = string(Kazakh)
+	}
+
+	lang := Lang(code)
+	if _, ok := catalogs[lang]; !ok {
+		return "", false
+	}
+	return lang, true
+}
+
+// Самый предпочтительный из поддерживаемых языков в заголовке Accept-Language,
+// например "kk-KZ,kk;q=0.9,ru;q=0.8"
+func FromAcceptLanguage(header string) (Lang, bool) {
+	type option struct {
+		lang Lang
+		q    float64
+	}
+
+	var options []option
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed <= 0 {
+				continue
+			}
+			q = parsed
+		}
+		options = append(options, option{lang, q})
+	}
+
+	if len(options) == 0 {
+		return "", false
+	}
+	sort.SliceStable(options, func(i, j int) bool { return options[i].q > options[j].q })
+	return options[0].lang, true
+}
+
+// Название языка на нем самом
+func (l Lang) Name() string {
+	return names[l]
+}
+
+// Сообщение по ключу. С аргументами сообщение используется как формат fmt.Sprintf.
+// Если ключа нет ни в каталоге языка, ни в каталоге по умолчанию, возвращается сам ключ.
+func (l Lang) T(key string, args ...any) string {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Сообщение с числом n в нужной форме множественного числа. В каталоге
+// формы хранятся под ключами key.one, key.few, key.many (русский) и
+// key.one, key.other (казахский, английский). Без аргументов в формат подставляется n.
+func (l Lang) N(key string, n int, args ...any) string {
+	if len(args) == 0 {
+		args = []any{n}
+	}
+
+	form := key + "." + l.pluralForm(n)
+	if _, ok := catalogs[l][form]; !ok {
+		form = key + ".other"
+	}
+	return l.T(form, args...)
+}
+
+// Форма множественного числа по правилам языка
+func (l Lang) pluralForm(n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch l {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
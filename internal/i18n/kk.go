@@ -0,0 +1,226 @@
+package i18n
+
+// Казахский каталог
+var kk = map[string]string{
+	// Страницы регистрации
+	"web.title.register":        "Тіркеу",
+	"web.title.success":         "Тіркеу аяқталды",
+	"web.title.error":           "Қате",
+	"web.register.heading":      "Тіркеу формасы",
+	"web.register.name":         "Аты:",
+	"web.register.phone":        "Телефон:",
+	"web.register.birthday":     "Туған күні:",
+	"web.register.submit":       "Жіберу",
+	"web.register.rejected":     "Көрсетілген деректермен клиентті тіркеу мүмкін болмады. Оларды тексеріп, қайтадан көріңіз.",
+	"web.success.heading":       "Тіркеу сәтті өтті.",
+	"web.success.visit":         "Пайдалану үшін біздің кофеханаға келіп, деректеріңізді айту жеткілікті.",
+	"web.success.wallet":        "Ыңғайлы болу үшін бонустары бар виртуалды картаны әмиянға қосып, ұпайларыңызды әрдайым көріп, адалдық бағдарламамызды пайдалана аласыз.",
+	"web.success.wallet_prefix": "Ол үшін жай ғана",
+	"web.success.wallet_link":   "картаны әмиянға орнатыңыз",
+	"web.success.bonus.one":     "Енді шотыңызда біздің кофеханада кофеге жұмсауға болатын %d бонустық ұпай бар.",
+	"web.success.bonus.other":   "Енді шотыңызда біздің кофеханада кофеге жұмсауға болатын %d бонустық ұпай бар.",
+	"web.pending.heading":       "Тіркеуге өтінім қабылданды.",
+	"web.pending.text":          "Бонустар жақын арада есептеледі, форманы қайта жіберудің қажеті жоқ.",
+	"web.error.heading":         "Тіркеу қатесі",
+	"web.error.help":            "Егер сіз осы сілтеме бойынша алғаш рет тіркелсеңіз, көмек алу үшін іс-шара ұйымдастырушыларына хабарласыңыз.",
+
+	// Причины, по которым ссылка не действует
+	"token.expired": "Бұл сілтеменің мерзімі өтіп кетті.",
+	"token.used":    "Бұл сілтеме тіркелу үшін бұрын пайдаланылған.",
+	"token.revoked": "Бұл сілтемені ұйымдастырушылар кері қайтарып алды.",
+	"token.invalid": "Сілтеме жарамсыз.",
+
+	// Проверка полей формы
+	"form.name.required":          "Атыңызды көрсетіңіз.",
+	"form.name.length":            "Аты %d-ден %d-ге дейін таңбадан тұруы керек.",
+	"form.name.chars":             "Атында тек әріптер, бос орындар, дефис, апостроф және нүкте болуы мүмкін.",
+	"form.birthday.required":      "Туған күніңізді көрсетіңіз.",
+	"form.birthday.format":        "Туған күннің пішімі қате.",
+	"form.birthday.future":        "Туған күн болашақта бола алмайды.",
+	"form.birthday.too_old":       "Туған жылыңызды тексеріңіз.",
+	"form.birthday.min_age.one":   "Тіркелу %d жастан бастап қолжетімді.",
+	"form.birthday.min_age.other": "Тіркелу %d жастан бастап қолжетімді.",
+	"form.phone.required":         "Телефон нөміріңізді көрсетіңіз.",
+	"form.phone.operator":         "Оператор коды қате!",
+	"form.phone.format":           "Телефон нөмірінің пішімі қате!",
+
+	// Общие ответы бота
+	"bot.no_access":               "Бұл команданы пайдалануға құқығыңыз жоқ.",
+	"bot.callback.no_access":      "Қолжетімділік жоқ.",
+	"bot.campaign.not_found":      "Науқан табылмады. Науқандар тізімі: /campaigns",
+	"bot.campaign.not_found_code": "«%s» науқаны табылмады. Науқандар тізімі: /campaigns",
+	"bot.conversation.expired":    "Жауап күту уақыты өтіп кетті. Команданы қайталаңыз.",
+	"bot.conversation.unknown":    "Хабарламаны түсінбедім. Алдымен команданы таңдаңыз, мысалы /check_token.",
+	"bot.cancel.nothing":          "Болдырмайтын әрекет жоқ.",
+	"bot.cancel.done":             "Әрекет болдырылмады.",
+
+	// Выдача ссылок
+	"bot.register.bad_ttl":   "Жарамдылық мерзімі қате. Мысал: /register 48h qr",
+	"bot.register.error":     "Сілтеме жасау кезінде қате шықты",
+	"bot.register.link":      "Сіздің сілтемеңіз: %s",
+	"bot.register.qr_failed": "QR-кодты жіберу мүмкін болмады. Сіздің сілтемеңіз: %s",
+	"bot.batch.usage":        "Сілтемелер санын 1-ден %d-ге дейін көрсетіңіз. Мысал: /register_batch 50 opening ашылу",
+	"bot.batch.error":        "Сілтемелерді жасау кезінде қате шықты",
+	"bot.batch.file_error":   "Сілтемелер файлын жасау кезінде қате шықты",
+	"bot.batch.send_error":   "Сілтемелер файлын жіберу кезінде қате шықты",
+	"bot.batch.created":      "Жасалған сілтемелер: %d",
+	"bot.batch.label":        "Белгі: %s",
+	"bot.my_links.error":     "Сілтемелер тізімін алу кезінде қате шықты.",
+	"bot.my_links.none":      "Сіз әлі сілтеме берген жоқсыз.",
+	"bot.my_links.title":     "🔗 *Сіздің соңғы сілтемелеріңіз:*",
+	"bot.qr.failed":          "QR-кодты жіберу мүмкін болмады. Сілтеме: %s",
+
+	// Кампании
+	"bot.campaigns.error":         "Науқандар тізімін алу кезінде қате шықты.",
+	"bot.campaigns.title":         "📣 Науқандар:",
+	"bot.campaigns.item":          "🔸 %s — %d бонус, топ %d",
+	"bot.campaigns.language":      ", беттер тілі: %s",
+	"bot.add_campaign.usage":      "Пішім: /add_campaign код бонустар топ [сәтті бет мәтіні]\nМысал: /add_campaign partner 500 3 Сізге 500 бонус есептелді!",
+	"bot.add_campaign.exists":     "Мұндай коды бар науқан бұрыннан бар.",
+	"bot.add_campaign.error":      "Науқанды жасау кезінде қате шықты.",
+	"bot.add_campaign.created":    "«%s» науқаны жасалды.",
+	"bot.campaign_language.usage": "Пішім: /campaign_language код ru|kk|en|-\nТіл клиент браузері қолдау көрсетілетін тілдердің ешқайсысын хабарламаса қолданылады; «-» — орыс тілі.\nМысал: /campaign_language opening kk",
+	"bot.campaign_language.error": "Науқан тілін өзгерту кезінде қате шықты.",
+	"bot.campaign_language.set":   "Клиент браузері қолдау көрсетілетін тілді хабарламаса, «%s» науқанының тіркеу беттері мына тілде көрсетіледі: %s.",
+	"bot.campaign_language.reset": "«%s» науқанының тілі тасталды: беттер клиент браузерінің тілінде, болмаса орыс тілінде көрсетіледі.",
+
+	// Отзыв и проверка токенов
+	"bot.revoke.usage":       "Пішім: /revoke токен [себеп]",
+	"bot.revoke.done":        "Токен кері қайтарылды, сілтеме енді жарамсыз.",
+	"bot.revoke.used":        "Токен пайдаланылған, оны кері қайтару мүмкін емес.",
+	"bot.revoke.already":     "Токен бұрын кері қайтарылған.",
+	"bot.revoke.error":       "Токенді кері қайтару кезінде қате шықты.",
+	"bot.revoke.confirm":     "%s токенін кері қайтару керек пе? Сілтеме жарамсыз болады.",
+	"bot.revoke.result":      "%s токені: %s",
+	"bot.revoke.cancelled":   "Кері қайтару болдырылмады.",
+	"bot.token.not_found":    "Токен табылмады.",
+	"bot.token.lookup_error": "Токен бойынша деректерді іздеу кезінде қате шықты.",
+	"bot.check_token.prompt": "Тексеру үшін токенді енгізіңіз (болдырмау үшін /cancel):",
+	"bot.tokens.error":       "Токендер тізімін алу кезінде қате шықты.",
+	"bot.tokens.no_used":     "Пайдаланылған токендер жоқ.",
+	"bot.tokens.no_unused":   "Пайдаланылмаған токендер жоқ.",
+
+	// Списки токенов и кнопки
+	"tokens.title.unused":          "🟢 *Пайдаланылмаған токендер*",
+	"tokens.title.used":            "📌 *Пайдаланылған токендер*",
+	"tokens.page":                  "%s (%d), бет %d / %d:",
+	"tokens.button.revoke":         "🚫 Кері қайтару",
+	"tokens.button.prev":           "◀️ Артқа",
+	"tokens.button.next":           "Алға ▶️",
+	"tokens.button.revoke_confirm": "🚫 Иә, кері қайтару",
+	"tokens.button.cancel":         "Болдырмау",
+	"status.active":                "🟢 жарамды",
+	"status.used":                  "✅ пайдаланылды",
+	"status.revoked":               "🚫 кері қайтарылды",
+	"status.expired":               "⌛ мерзімі өтті",
+
+	// Сведения о токенах и регистрациях
+	"issuer.unknown":         "белгісіз",
+	"details.title":          "Токен деректері:",
+	"details.status":         "Күйі: %s",
+	"details.campaign":       "📣 Науқан: %s",
+	"details.label":          "📦 Белгі: %s",
+	"details.issued_by":      "🔑 Берген: %s",
+	"details.issued_at":      "🕒 Берілген уақыты: %s",
+	"details.expires_at":     "⏳ Жарамдылық мерзімі: %s",
+	"details.revoked_by":     "🚫 Кері қайтарған: ID %d",
+	"details.revoked_reason": ", себебі: %s",
+	"details.name":           "👤 Аты: %s",
+	"details.phone":          "📞 Телефон: %s",
+	"details.client":         "🆔 Poster клиенті: %d",
+	"details.pending":        "Poster-дегі тіркеу әлі аяқталған жоқ, /stuck_jobs қараңыз",
+	"bot.find.usage":         "Пішім: /find телефон немесе /find аты\nМысал: /find 87771234567",
+	"bot.find.error":         "Тіркеулерді іздеу кезінде қате шықты.",
+	"bot.find.none":          "Тіркеулер табылмады.",
+	"matches.found":          "🔎 Табылған тіркеулер: %d",
+	"matches.truncated":      "соңғылары көрсетілген, сұранысты нақтылаңыз",
+	"matches.token":          "🔑 Токен: %s",
+	"matches.date":           "🕒 Күні: %s",
+	"matches.issued_by":      "Берген: %s",
+
+	// Уведомления о регистрациях
+	"bot.notify.error":       "Хабарлама баптауларын өзгерту кезінде қате шықты.",
+	"bot.notify.on":          "Жаңа тіркеулер туралы хабарламалар қосылды.",
+	"bot.notify.off":         "Жаңа тіркеулер туралы хабарламалар өшірілді.",
+	"bot.notify.shared_chat": "Қазір хабарламалар ортақ чатқа жіберіледі, жеке баптау болашаққа сақталды.",
+	"notice.title":           "✅ Жаңа тіркеу",
+	"notice.client":          "🆔 Poster клиенті: %d (%s)",
+	"notice.client_new":      "жаңа клиент",
+	"notice.client_existed":  "клиент Poster-де бұрыннан бар",
+	"notice.issued_by":       "🔑 Сілтемені берген: %s",
+
+	// Зависшие регистрации
+	"bot.jobs.error":      "Тапсырмалар тізімін алу кезінде қате шықты.",
+	"bot.jobs.none":       "Тоқтап қалған тіркеулер жоқ.",
+	"bot.jobs.title":      "⚠️ Тоқтап қалған тіркеулер:",
+	"bot.jobs.item":       "#%d %s, %s\nҚадам: %s, әрекеттер: %d (%s)\nҚате: %s",
+	"bot.jobs.retry_at":   "қайталау %s",
+	"bot.jobs.failed":     "әрекеттер таусылды",
+	"bot.jobs.retry_hint": "Қайталау: /retry_job нөмір",
+	"bot.retry.usage":     "Пішім: /retry_job нөмір",
+	"bot.retry.not_found": "Тапсырма табылмады.",
+	"bot.retry.busy":      "Тапсырма қазір өңделуде, кейінірек қайталаңыз.",
+	"bot.retry.failed":    "Қайталау сәтсіз аяқталды: %s",
+	"bot.retry.done":      "Тіркеу аяқталды.",
+
+	// Статистика
+	"bot.stats.usage":                 "Күн санын 1-ден %d-ге дейін көрсетіңіз. Мысал: /stats 30",
+	"bot.stats.error":                 "Статистиканы алу кезінде қате шықты.",
+	"stats.empty.one":                 "Соңғы %d күнде оқиғалар болған жоқ.",
+	"stats.empty.other":               "Соңғы %d күнде оқиғалар болған жоқ.",
+	"stats.title.one":                 "📊 %d күндегі статистика",
+	"stats.title.other":               "📊 %d күндегі статистика",
+	"stats.by_day":                    "Күндер бойынша:",
+	"stats.by_campaign":               "Науқандар бойынша:",
+	"stats.no_campaign":               "науқансыз",
+	"stats.conversion":                "конверсия %d%%",
+	"stats.reasons":                   "Қателердің себептері:",
+	"stats.step.issued":               "берілді",
+	"stats.step.opened":               "ашылды",
+	"stats.step.submitted":            "жіберілді",
+	"stats.step.created":              "жаңа",
+	"stats.step.found":                "табылды",
+	"stats.step.awarded":              "бонустар",
+	"stats.step.failed":               "қателер",
+	"stats.reason.poster_auth":        "Poster қолжетімділік токенін қабылдамады",
+	"stats.reason.poster_validation":  "Poster клиент деректерін қабылдамады",
+	"stats.reason.poster_rate_limit":  "Poster сұраныс шегі асып кетті",
+	"stats.reason.poster_unavailable": "Poster қолжетімсіз",
+	"stats.reason.internal":           "ішкі қате",
+
+	// Выгрузка регистраций
+	"bot.export.bad_args":     "Қате: %s.\nМысал: /export 2024-05-01 2024-05-31 opening xlsx",
+	"bot.export.error":        "Тіркеулерді жүктеп алу кезінде қате шықты.",
+	"bot.export.empty":        "Таңдалған кезеңде тіркеулер жоқ.",
+	"bot.export.file_error":   "Жүктеу файлын жасау кезінде қате шықты.",
+	"bot.export.send_error":   "Жүктеу файлын жіберу кезінде қате шықты.",
+	"export.extra_date":       "артық күн %s",
+	"export.unknown_arg":      "түсініксіз аргумент %s",
+	"export.bad_period":       "аяқталу күні басталу күнінен ерте",
+	"export.caption.count":    "Тіркеулер: %d",
+	"export.caption.period":   "Кезең: %s — %s",
+	"export.caption.since":    "%s бастап",
+	"export.caption.campaign": "Науқан: %s",
+
+	// Администраторы
+	"bot.admins.error":            "Әкімшілер тізімін алу кезінде қате шықты.",
+	"admins.title":                "👥 Әкімшілер:",
+	"role.owner":                  "👑 иесі",
+	"role.manager":                "🛠 менеджер",
+	"role.issuer":                 "🔑 сілтеме беру",
+	"role.readonly":               "👀 тек қарау",
+	"bot.add_admin.usage":         "Пайдаланушы ID-і мен рөлін көрсетіңіз: owner, manager, issuer немесе readonly. Мысал: /add_admin 123456789 issuer barista\nПайдаланушының қайта жіберілген хабарламасына /add_admin рөл командасымен жауап беруге болады.",
+	"bot.add_admin.last_owner":    "Жалғыз иесінің рөлін төмендетуге болмайды. Алдымен басқа иесін тағайындаңыз.",
+	"bot.add_admin.error":         "Әкімшіні қосу кезінде қате шықты.",
+	"bot.add_admin.done":          "Әкімші %s: %s",
+	"bot.remove_admin.usage":      "Әкімшінің ID-ін көрсетіңіз. Мысал: /remove_admin 123456789",
+	"bot.remove_admin.not_found":  "Әкімші табылмады.",
+	"bot.remove_admin.last_owner": "Жалғыз иесін жоюға болмайды.",
+	"bot.remove_admin.error":      "Әкімшіні жою кезінде қате шықты.",
+	"bot.remove_admin.done":       "Әкімші жойылды.",
+
+	// Язык ответов бота
+	"bot.language.current": "Бот жауаптарының тілі: %s.\nӨзгерту: /language ru|kk|en",
+	"bot.language.error":   "Тілді өзгерту кезінде қате шықты.",
+	"bot.language.set":     "Бот қазақ тілінде жауап береді.",
+}
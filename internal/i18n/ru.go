@@ -0,0 +1,230 @@
+package i18n
+
+// Русский каталог. Он же используется для сообщений, которых нет в других каталогах.
+var ru = map[string]string{
+	// Страницы регистрации
+	"web.title.register":        "Регистрация",
+	"web.title.success":         "Регистрация завершена",
+	"web.title.error":           "Ошибка",
+	"web.register.heading":      "Форма регистрации",
+	"web.register.name":         "Имя:",
+	"web.register.phone":        "Телефон:",
+	"web.register.birthday":     "Дата рождения:",
+	"web.register.submit":       "Отправить",
+	"web.register.rejected":     "Не удалось зарегистрировать клиента с указанными данными. Проверьте их и попробуйте ещё раз.",
+	"web.success.heading":       "Регистрация прошла успешно.",
+	"web.success.visit":         "Для использования достаточно прийти к нам в кофейню и назвать свои данные.",
+	"web.success.wallet":        "Для вашего удобства, вы также можете добавить виртуальную карту с бонусами в свой кошелек, чтобы всегда иметь доступ к своим баллам и пользоваться нашей системой лояльности.",
+	"web.success.wallet_prefix": "Для этого просто",
+	"web.success.wallet_link":   "установите карту в кошелек",
+	"web.success.bonus.one":     "Теперь на вашем счёте %d бонусный балл, который можно потратить на кофе в нашей кофейне.",
+	"web.success.bonus.few":     "Теперь на вашем счёте %d бонусных балла, которые можно потратить на кофе в нашей кофейне.",
+	"web.success.bonus.many":    "Теперь на вашем счёте %d бонусных баллов, которые можно потратить на кофе в нашей кофейне.",
+	"web.pending.heading":       "Заявка на регистрацию принята.",
+	"web.pending.text":          "Бонусы будут начислены в ближайшее время, повторно отправлять форму не нужно.",
+	"web.error.heading":         "Ошибка регистрации",
+	"web.error.help":            "Если вы регистрируетесь по ней впервые, пожалуйста, обратитесь к организаторам мероприятия для получения помощи.",
+
+	// Причины, по которым ссылка не действует
+	"token.expired": "Срок действия этой ссылки истёк.",
+	"token.used":    "Эта ссылка уже была использована для регистрации.",
+	"token.revoked": "Эта ссылка была отозвана организаторами.",
+	"token.invalid": "Ссылка недействительна.",
+
+	// Проверка полей формы
+	"form.name.required":         "Укажите имя.",
+	"form.name.length":           "Имя должно содержать от %d до %d символов.",
+	"form.name.chars":            "Имя может содержать только буквы, пробелы, дефис, апостроф и точку.",
+	"form.birthday.required":     "Укажите дату рождения.",
+	"form.birthday.format":       "Неверный формат даты рождения.",
+	"form.birthday.future":       "Дата рождения не может быть в будущем.",
+	"form.birthday.too_old":      "Проверьте год рождения.",
+	"form.birthday.min_age.one":  "Регистрация доступна с %d года.",
+	"form.birthday.min_age.few":  "Регистрация доступна с %d лет.",
+	"form.birthday.min_age.many": "Регистрация доступна с %d лет.",
+	"form.phone.required":        "Укажите номер телефона.",
+	"form.phone.operator":        "Неверный код оператора!",
+	"form.phone.format":          "Неверный формат телефона!",
+
+	// Общие ответы бота
+	"bot.no_access":               "У вас нет прав для использования этой команды.",
+	"bot.callback.no_access":      "Нет доступа.",
+	"bot.campaign.not_found":      "Кампания не найдена. Список кампаний: /campaigns",
+	"bot.campaign.not_found_code": "Кампания «%s» не найдена. Список кампаний: /campaigns",
+	"bot.conversation.expired":    "Время ожидания ответа истекло. Повторите команду.",
+	"bot.conversation.unknown":    "Не понимаю сообщение. Сначала выберите команду, например /check_token.",
+	"bot.cancel.nothing":          "Нечего отменять.",
+	"bot.cancel.done":             "Действие отменено.",
+
+	// Выдача ссылок
+	"bot.register.bad_ttl":   "Неверный срок действия. Пример: /register 48h qr",
+	"bot.register.error":     "Ошибка при создании ссылки",
+	"bot.register.link":      "Ваша ссылка: %s",
+	"bot.register.qr_failed": "Не удалось отправить QR-код. Ваша ссылка: %s",
+	"bot.batch.usage":        "Укажите количество ссылок от 1 до %d. Пример: /register_batch 50 opening открытие",
+	"bot.batch.error":        "Ошибка при создании ссылок",
+	"bot.batch.file_error":   "Ошибка при создании файла со ссылками",
+	"bot.batch.send_error":   "Ошибка при отправке файла со ссылками",
+	"bot.batch.created":      "Создано ссылок: %d",
+	"bot.batch.label":        "Метка: %s",
+	"bot.my_links.error":     "Ошибка при получении списка ссылок.",
+	"bot.my_links.none":      "Вы ещё не выдавали ссылок.",
+	"bot.my_links.title":     "🔗 *Ваши последние ссылки:*",
+	"bot.qr.failed":          "Не удалось отправить QR-код. Ссылка: %s",
+
+	// Кампании
+	"bot.campaigns.error":         "Ошибка при получении списка кампаний.",
+	"bot.campaigns.title":         "📣 Кампании:",
+	"bot.campaigns.item":          "🔸 %s — %d бонусов, группа %d",
+	"bot.campaigns.language":      ", язык страниц: %s",
+	"bot.add_campaign.usage":      "Формат: /add_campaign код бонусы группа [текст страницы успеха]\nПример: /add_campaign partner 500 3 Вам начислено 500 бонусов!",
+	"bot.add_campaign.exists":     "Кампания с таким кодом уже существует.",
+	"bot.add_campaign.error":      "Ошибка при создании кампании.",
+	"bot.add_campaign.created":    "Кампания «%s» создана.",
+	"bot.campaign_language.usage": "Формат: /campaign_language код ru|kk|en|-\nЯзык используется, если браузер клиента не сообщил ни одного из поддерживаемых языков; «-» — русский.\nПример: /campaign_language opening kk",
+	"bot.campaign_language.error": "Ошибка при изменении языка кампании.",
+	"bot.campaign_language.set":   "Если браузер клиента не сообщил поддерживаемый язык, страницы регистрации кампании «%s» показываются на языке: %s.",
+	"bot.campaign_language.reset": "Язык кампании «%s» сброшен: страницы показываются на языке браузера клиента, иначе на русском.",
+
+	// Отзыв и проверка токенов
+	"bot.revoke.usage":       "Формат: /revoke токен [причина]",
+	"bot.revoke.done":        "Токен отозван, ссылка больше не действует.",
+	"bot.revoke.used":        "Токен уже использован, отозвать его нельзя.",
+	"bot.revoke.already":     "Токен уже отозван.",
+	"bot.revoke.error":       "Ошибка при отзыве токена.",
+	"bot.revoke.confirm":     "Отозвать токен %s? Ссылка перестанет действовать.",
+	"bot.revoke.result":      "Токен %s: %s",
+	"bot.revoke.cancelled":   "Отзыв отменён.",
+	"bot.token.not_found":    "Токен не найден.",
+	"bot.token.lookup_error": "Ошибка при поиске данных по токену.",
+	"bot.check_token.prompt": "Введите токен для проверки (или /cancel для отмены):",
+	"bot.tokens.error":       "Ошибка при получении списка токенов.",
+	"bot.tokens.no_used":     "Нет использованных токенов.",
+	"bot.tokens.no_unused":   "Нет неиспользованных токенов.",
+
+	// Списки токенов и кнопки
+	"tokens.title.unused":          "🟢 *Неиспользованные токены*",
+	"tokens.title.used":            "📌 *Использованные токены*",
+	"tokens.page":                  "%s (%d), стр. %d из %d:",
+	"tokens.button.revoke":         "🚫 Отозвать",
+	"tokens.button.prev":           "◀️ Назад",
+	"tokens.button.next":           "Вперёд ▶️",
+	"tokens.button.revoke_confirm": "🚫 Да, отозвать",
+	"tokens.button.cancel":         "Отмена",
+	"status.active":                "🟢 действует",
+	"status.used":                  "✅ использована",
+	"status.revoked":               "🚫 отозвана",
+	"status.expired":               "⌛ истекла",
+
+	// Сведения о токенах и регистрациях
+	"issuer.unknown":         "неизвестно",
+	"details.title":          "Данные по токену:",
+	"details.status":         "Статус: %s",
+	"details.campaign":       "📣 Кампания: %s",
+	"details.label":          "📦 Метка: %s",
+	"details.issued_by":      "🔑 Выдал: %s",
+	"details.issued_at":      "🕒 Выдана: %s",
+	"details.expires_at":     "⏳ Действует до: %s",
+	"details.revoked_by":     "🚫 Отозвал: ID %d",
+	"details.revoked_reason": ", причина: %s",
+	"details.name":           "👤 Имя: %s",
+	"details.phone":          "📞 Телефон: %s",
+	"details.client":         "🆔 Клиент Poster: %d",
+	"details.pending":        "Регистрация ещё не завершена в Poster, см. /stuck_jobs",
+	"bot.find.usage":         "Формат: /find телефон или /find имя\nПример: /find 87771234567",
+	"bot.find.error":         "Ошибка при поиске регистраций.",
+	"bot.find.none":          "Регистраций не найдено.",
+	"matches.found":          "🔎 Найдено регистраций: %d",
+	"matches.truncated":      "показаны последние, уточните запрос",
+	"matches.token":          "🔑 Токен: %s",
+	"matches.date":           "🕒 Дата: %s",
+	"matches.issued_by":      "Выдал: %s",
+
+	// Уведомления о регистрациях
+	"bot.notify.error":       "Ошибка при изменении настроек уведомлений.",
+	"bot.notify.on":          "Уведомления о новых регистрациях включены.",
+	"bot.notify.off":         "Уведомления о новых регистрациях отключены.",
+	"bot.notify.shared_chat": "Сейчас уведомления отправляются в общий чат, личная настройка сохранена на будущее.",
+	"notice.title":           "✅ Новая регистрация",
+	"notice.client":          "🆔 Клиент Poster: %d (%s)",
+	"notice.client_new":      "новый клиент",
+	"notice.client_existed":  "клиент уже был в Poster",
+	"notice.issued_by":       "🔑 Ссылку выдал: %s",
+
+	// Зависшие регистрации
+	"bot.jobs.error":      "Ошибка при получении списка задач.",
+	"bot.jobs.none":       "Зависших регистраций нет.",
+	"bot.jobs.title":      "⚠️ Зависшие регистрации:",
+	"bot.jobs.item":       "#%d %s, %s\nШаг: %s, попыток: %d (%s)\nОшибка: %s",
+	"bot.jobs.retry_at":   "повтор %s",
+	"bot.jobs.failed":     "попытки исчерпаны",
+	"bot.jobs.retry_hint": "Повторить: /retry_job номер",
+	"bot.retry.usage":     "Формат: /retry_job номер",
+	"bot.retry.not_found": "Задача не найдена.",
+	"bot.retry.busy":      "Задача сейчас обрабатывается, повторите позже.",
+	"bot.retry.failed":    "Повтор не удался: %s",
+	"bot.retry.done":      "Регистрация завершена.",
+
+	// Статистика
+	"bot.stats.usage":                 "Укажите количество дней от 1 до %d. Пример: /stats 30",
+	"bot.stats.error":                 "Ошибка при получении статистики.",
+	"stats.empty.one":                 "За последний %d день событий нет.",
+	"stats.empty.few":                 "За последние %d дня событий нет.",
+	"stats.empty.many":                "За последние %d дней событий нет.",
+	"stats.title.one":                 "📊 Статистика за %d день",
+	"stats.title.few":                 "📊 Статистика за %d дня",
+	"stats.title.many":                "📊 Статистика за %d дней",
+	"stats.by_day":                    "По дням:",
+	"stats.by_campaign":               "По кампаниям:",
+	"stats.no_campaign":               "без кампании",
+	"stats.conversion":                "конверсия %d%%",
+	"stats.reasons":                   "Причины ошибок:",
+	"stats.step.issued":               "выдано",
+	"stats.step.opened":               "открыто",
+	"stats.step.submitted":            "отправлено",
+	"stats.step.created":              "новых",
+	"stats.step.found":                "найдено",
+	"stats.step.awarded":              "бонусы",
+	"stats.step.failed":               "ошибки",
+	"stats.reason.poster_auth":        "Poster отклонил токен доступа",
+	"stats.reason.poster_validation":  "Poster отклонил данные клиента",
+	"stats.reason.poster_rate_limit":  "превышен лимит запросов Poster",
+	"stats.reason.poster_unavailable": "Poster недоступен",
+	"stats.reason.internal":           "внутренняя ошибка",
+
+	// Выгрузка регистраций
+	"bot.export.bad_args":     "Ошибка: %s.\nПример: /export 2024-05-01 2024-05-31 opening xlsx",
+	"bot.export.error":        "Ошибка при выгрузке регистраций.",
+	"bot.export.empty":        "За выбранный период регистраций нет.",
+	"bot.export.file_error":   "Ошибка при создании файла выгрузки.",
+	"bot.export.send_error":   "Ошибка при отправке файла выгрузки.",
+	"export.extra_date":       "лишняя дата %s",
+	"export.unknown_arg":      "непонятный аргумент %s",
+	"export.bad_period":       "дата окончания раньше даты начала",
+	"export.caption.count":    "Регистраций: %d",
+	"export.caption.period":   "Период: %s — %s",
+	"export.caption.since":    "С %s",
+	"export.caption.campaign": "Кампания: %s",
+
+	// Администраторы
+	"bot.admins.error":            "Ошибка при получении списка администраторов.",
+	"admins.title":                "👥 Администраторы:",
+	"role.owner":                  "👑 владелец",
+	"role.manager":                "🛠 менеджер",
+	"role.issuer":                 "🔑 выдача ссылок",
+	"role.readonly":               "👀 только просмотр",
+	"bot.add_admin.usage":         "Укажите ID пользователя и роль: owner, manager, issuer или readonly. Пример: /add_admin 123456789 issuer barista\nМожно ответить командой /add_admin роль на пересланное сообщение пользователя.",
+	"bot.add_admin.last_owner":    "Нельзя понизить единственного владельца. Сначала назначьте другого владельца.",
+	"bot.add_admin.error":         "Ошибка при добавлении администратора.",
+	"bot.add_admin.done":          "Администратор %s: %s",
+	"bot.remove_admin.usage":      "Укажите ID администратора. Пример: /remove_admin 123456789",
+	"bot.remove_admin.not_found":  "Администратор не найден.",
+	"bot.remove_admin.last_owner": "Нельзя удалить единственного владельца.",
+	"bot.remove_admin.error":      "Ошибка при удалении администратора.",
+	"bot.remove_admin.done":       "Администратор удалён.",
+
+	// Язык ответов бота
+	"bot.language.current": "Язык ответов бота: %s.\nСменить: /language ru|kk|en",
+	"bot.language.error":   "Ошибка при изменении языка.",
+	"bot.language.set":     "Бот будет отвечать на русском.",
+}
@@ -0,0 +1,87 @@
+// Пакет metrics собирает метрики приложения в формате Prometheus
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "links_bot"
+
+// Отдельный реестр, чтобы в /metrics попадали только метрики приложения, Go и процесса
+var registry = prometheus.NewRegistry()
+
+var (
+	linksGenerated = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "links_generated_total",
+		Help:      "Количество выданных ссылок для регистрации.",
+	})
+
+	registrationsSucceeded = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_succeeded_total",
+		Help:      "Количество регистраций, доведенных до конца.",
+	})
+
+	registrationsFailed = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_failed_total",
+		Help:      "Количество регистраций, требующих вмешательства администратора, по причинам.",
+	}, []string{"reason"})
+
+	posterRequestDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "poster_request_duration_seconds",
+		Help:      "Длительность запросов к Poster API по методам и результатам.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "outcome"})
+
+	botCommands = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bot_commands_total",
+		Help:      "Количество вызовов команд бота.",
+	}, []string{"command"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Обработчик /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Выдана ссылка
+func LinkGenerated() {
+	linksGenerated.Inc()
+}
+
+// Регистрация завершена
+func RegistrationSucceeded() {
+	registrationsSucceeded.Inc()
+}
+
+// Регистрация не удалась по причине reason
+func RegistrationFailed(reason string) {
+	registrationsFailed.WithLabelValues(reason).Inc()
+}
+
+// Запрос к методу Poster API занял d и завершился с результатом outcome
+func ObservePosterRequest(method, outcome string, d time.Duration) {
+	posterRequestDuration.WithLabelValues(method, outcome).Observe(d.Seconds())
+}
+
+// Вызвана команда бота
+func BotCommand(command string) {
+	botCommands.WithLabelValues(command).Inc()
+}
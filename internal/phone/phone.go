@@ -0,0 +1,166 @@
+package phone
+
+import (
+	"certificate/internal/domain"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Страны по умолчанию: Казахстан с кодами мобильных операторов
+const DefaultCountries = "7:8:10:700,701,702,705,706,707,708,747,771,775,776,777,778"
+
+// Длина кода оператора в национальном номере
+const operatorCodeLength = 3
+
+// Country — правила номеров одной страны
+type Country struct {
+	// Код страны без "+", например "7"
+	DialCode string
+	// Префикс для набора внутри страны, например "8" в 87771234567
+	TrunkPrefix string
+	// Количество цифр номера без кода страны
+	NationalLength int
+	// Допустимые коды операторов, пустой список — любой код
+	OperatorCodes []string
+}
+
+// Parser приводит номера к формату E.164 и проверяет коды операторов.
+// Номер без кода страны считается номером первой страны из списка.
+type Parser struct {
+	countries []Country
+}
+
+func NewParser(countries []Country) (*Parser, error) {
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("phone: at least one country is required")
+	}
+	return &Parser{countries: countries}, nil
+}
+
+// Начало номера для поля формы: код первой страны, например "+7 "
+func (p *Parser) Prefix() string {
+	return "+" + p.countries[0].DialCode + " "
+}
+
+// Разбор списка стран вида "код:префикс:длина:опер1,опер2;код:префикс:длина:..."
+func ParseCountries(spec string) ([]Country, error) {
+	var countries []Country
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("phone: country %q must be dial:trunk:length:operators", part)
+		}
+
+		dialCode := strings.TrimPrefix(strings.TrimSpace(fields[0]), "+")
+		if dialCode == "" || !isDigits(dialCode) {
+			return nil, fmt.Errorf("phone: invalid dial code %q", fields[0])
+		}
+		trunk := strings.TrimSpace(fields[1])
+		if !isDigits(trunk) {
+			return nil, fmt.Errorf("phone: invalid trunk prefix %q", fields[1])
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil || length <= operatorCodeLength || len(dialCode)+length > 15 {
+			return nil, fmt.Errorf("phone: invalid national number length %q", fields[2])
+		}
+
+		country := Country{DialCode: dialCode, TrunkPrefix: trunk, NationalLength: length}
+		for _, code := range strings.Split(fields[3], ",") {
+			code = strings.TrimSpace(code)
+			if code == "" {
+				continue
+			}
+			if len(code) != operatorCodeLength || !isDigits(code) {
+				return nil, fmt.Errorf("phone: invalid operator code %q", code)
+			}
+			country.OperatorCodes = append(country.OperatorCodes, code)
+		}
+		countries = append(countries, country)
+	}
+
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("phone: country list is empty")
+	}
+	return countries, nil
+}
+
+// Normalize приводит номер к формату E.164 (+77771234567).
+// Пробелы, скобки и дефисы игнорируются.
+func (p *Parser) Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	rest, international := strings.CutPrefix(raw, "+")
+
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+			return -1
+		default:
+			return 'x'
+		}
+	}, rest)
+	if digits == "" || strings.ContainsRune(digits, 'x') {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidPhone, raw)
+	}
+
+	country, national, ok := p.split(digits, international)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidPhone, raw)
+	}
+
+	if len(country.OperatorCodes) > 0 && !slices.Contains(country.OperatorCodes, national[:operatorCodeLength]) {
+		return "", fmt.Errorf("%w: %s", domain.ErrUnknownOperator, national[:operatorCodeLength])
+	}
+
+	return "+" + country.DialCode + national, nil
+}
+
+// Определение страны и национальной части номера
+func (p *Parser) split(digits string, international bool) (Country, string, bool) {
+	for _, c := range p.countries {
+		if national, ok := strings.CutPrefix(digits, c.DialCode); ok && len(national) == c.NationalLength {
+			return c, national, true
+		}
+	}
+	if international {
+		return Country{}, "", false
+	}
+
+	// Номер набран без кода страны: с префиксом или без него
+	home := p.countries[0]
+	if home.TrunkPrefix != "" {
+		if national, ok := strings.CutPrefix(digits, home.TrunkPrefix); ok && len(national) == home.NationalLength {
+			return home, national, true
+		}
+	}
+	if len(digits) == home.NationalLength {
+		return home, digits, true
+	}
+	return Country{}, "", false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Mask скрывает середину номера для уведомлений: +7777*****67
+func Mask(number string) string {
+	const head, tail = 5, 2
+	if len(number) <= head+tail {
+		return number
+	}
+	return number[:head] + strings.Repeat("*", len(number)-head-tail) + number[len(number)-tail:]
+}
@@ -0,0 +1,139 @@
+package phone
+
+import (
+	"certificate/internal/domain"
+	"errors"
+	"testing"
+)
+
+func newTestParser(t *testing.T, spec string) *Parser {
+	t.Helper()
+	countries, err := ParseCountries(spec)
+	if err != nil {
+		t.Fatalf("ParseCountries(%q): %v", spec, err)
+	}
+	p, err := NewParser(countries)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+	return p
+}
+
+func TestNormalize(t *testing.T) {
+	p := newTestParser(t, DefaultCountries)
+
+	tests := []struct {
+		raw  string
+		want string
+		err  error
+	}{
+		// Префиксы
+		{"87771234567", "+77771234567", nil},
+		{"+77771234567", "+77771234567", nil},
+		{"77771234567", "+77771234567", nil},
+		{"7771234567", "+77771234567", nil},
+		// Разделители
+		{"8 (777) 123-45-67", "+77771234567", nil},
+		{" +7 777 123 45 67 ", "+77771234567", nil},
+		{"8-701-123-45-67", "+77011234567", nil},
+		{"+7 (777) 123.45.67", "", domain.ErrInvalidPhone},
+		{"8777123456a", "", domain.ErrInvalidPhone},
+		{"", "", domain.ErrInvalidPhone},
+		// Неизвестный оператор
+		{"87271234567", "", domain.ErrUnknownOperator},
+		{"+74951234567", "", domain.ErrUnknownOperator},
+		// Неверная длина
+		{"877712345", "", domain.ErrInvalidPhone},
+		// Десять цифр после 8 читаются как номер без префикса
+		{"8777123456", "", domain.ErrUnknownOperator},
+		{"877712345678", "", domain.ErrInvalidPhone},
+		{"+7777123456", "", domain.ErrInvalidPhone},
+		{"+87771234567", "", domain.ErrInvalidPhone},
+		{"777123456", "", domain.ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		got, err := p.Normalize(tt.raw)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Normalize(%q) error = %v, want %v", tt.raw, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSeveralCountries(t *testing.T) {
+	p := newTestParser(t, "7:8:10:700,777;998::9:")
+
+	tests := []struct {
+		raw  string
+		want string
+		err  error
+	}{
+		{"87771234567", "+77771234567", nil},
+		{"+998 90 123 45 67", "+998901234567", nil},
+		// Номер без кода страны относится к первой стране
+		{"901234567", "", domain.ErrInvalidPhone},
+		{"+99890123456", "", domain.ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		got, err := p.Normalize(tt.raw)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Normalize(%q) error = %v, want %v", tt.raw, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseCountriesErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"7:8:10",
+		"x:8:10:777",
+		"7:x:10:777",
+		"7:8:3:777",
+		"7:8:15:777",
+		"7:8:10:77",
+	}
+
+	for _, spec := range tests {
+		if _, err := ParseCountries(spec); err == nil {
+			t.Errorf("ParseCountries(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{"+77771234567", "+7777*****67"},
+		{"+998901234567", "+9989******67"},
+		{"+1234567", "+1234*67"},
+		{"+123456", "+123456"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Mask(tt.number); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	if got := newTestParser(t, "998::9:;7:8:10:").Prefix(); got != "+998 " {
+		t.Errorf("Prefix() = %q, want %q", got, "+998 ")
+	}
+}
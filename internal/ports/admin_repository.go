@@ -0,0 +1,11 @@
+package ports
+
+import "certificate/internal/domain"
+
+type AdminRepository interface {
+	SaveAdmin(a *domain.Admin) error
+	GetAdmin(id int) (*domain.Admin, error)
+	ListAdmins() ([]domain.Admin, error)
+	DeleteAdmin(id int) error
+	CountOwners() (int, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+type AdminSettingsRepository interface {
+	SetNotifications(adminID int, enabled bool) error
+	NotificationsEnabled(adminID int) (bool, error)
+	SetLanguage(adminID int, language string) error
+	Language(adminID int) (string, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "certificate/internal/domain"
+
+type CampaignRepository interface {
+	CreateCampaign(c *domain.Campaign) error
+	GetCampaign(id int) (*domain.Campaign, error)
+	GetCampaignByCode(code string) (*domain.Campaign, error)
+	ListCampaigns() ([]domain.Campaign, error)
+	UpdateCampaignLanguage(id int, language string) error
+}
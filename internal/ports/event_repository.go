@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"certificate/internal/domain"
+	"time"
+)
+
+type EventRepository interface {
+	RecordEvents(events []domain.Event) error
+	GetFunnelStats(since time.Time) ([]domain.FunnelStats, error)
+	GetFailureReasons(since time.Time, limit int) ([]domain.ReasonCount, error)
+}
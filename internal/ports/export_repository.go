@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"certificate/internal/domain"
+	"time"
+)
+
+type ExportRepository interface {
+	GetRegistrationsForExport(from, to time.Time, campaignID int) ([]domain.ExportRow, error)
+}
@@ -0,0 +1,7 @@
+package ports
+
+import "certificate/internal/domain"
+
+type Notifier interface {
+	NotifyRegistration(n domain.RegistrationNotice)
+}
@@ -1,8 +1,13 @@
 package ports
 
-import "certificate/internal/domain"
+import (
+	"certificate/internal/domain"
+	"context"
+)
 
 type PosterAPI interface {
-	ChangeClientBonus(clientID int) error
+	ChangeClientBonus(clientID, amount int) error
+	FindClientByPhone(phone string) (int, error)
 	CreateClient(c domain.Client) (int, error)
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"certificate/internal/domain"
+	"time"
+)
+
+type RegistrationJobRepository interface {
+	EnqueueRegistration(job *domain.RegistrationJob) error
+	ClaimJob(job *domain.RegistrationJob, now, leaseUntil time.Time) error
+	UpdateJob(job *domain.RegistrationJob) error
+	CompleteJob(job *domain.RegistrationJob) error
+	CancelJob(job *domain.RegistrationJob) error
+	GetJob(id int) (*domain.RegistrationJob, error)
+	GetDueJobs(now time.Time, limit int) ([]domain.RegistrationJob, error)
+	GetStuckJobs() ([]domain.RegistrationJob, error)
+}
@@ -1,12 +1,23 @@
 package ports
 
-import "certificate/internal/domain"
+import (
+	"certificate/internal/domain"
+	"context"
+	"time"
+)
 
 type RegistrationRepository interface {
-	Create(token string) error
+	Create(reg *domain.Registration) error
+	CreateBatch(regs []*domain.Registration) error
 	GetByToken(token string) (*domain.Registration, error)
-	MarkTokenUsed(token, name, phone string) error
+	GetByID(id int) (*domain.Registration, error)
+	RevokeToken(token, reason string, adminID int, revokedAt time.Time) error
 	GetTokenUsage(token string) (*domain.TokenUsage, error)
+	FindUsageByPhone(phone string, limit int) ([]domain.RegistrationMatch, error)
+	FindUsageByName(name string, limit int) ([]domain.RegistrationMatch, error)
 	GetUsedTokens() ([]domain.Registration, error)
 	GetUnusedTokens() ([]domain.Registration, error)
+	GetTokensPage(used bool, offset, limit int) ([]domain.Registration, int, error)
+	GetTokensIssuedBy(adminID, limit int) ([]domain.Registration, error)
+	Ping(ctx context.Context) error
 }
@@ -1,12 +1,44 @@
 package ports
 
-import "certificate/internal/domain"
+import (
+	"certificate/internal/domain"
+	"context"
+)
 
 type RegistrationService interface {
-	GenerateUniqueLink(baseURL string) (string, error)
-	RegisterUser(token, name, phone, birthday string) error
+	GenerateUniqueLink(baseURL string, opts domain.LinkOptions) (string, error)
+	GenerateLinkBatch(baseURL string, count int, opts domain.LinkOptions) ([]domain.Link, error)
+	RegisterUser(token, name, phone, birthday string) (*domain.Campaign, error)
 	GetTokenUsage(token string) (*domain.TokenUsage, error)
+	GetTokenDetails(token string) (*domain.TokenDetails, error)
+	FindRegistrationsByPhone(phone string) ([]domain.RegistrationMatch, error)
+	FindRegistrationsByName(name string) ([]domain.RegistrationMatch, error)
+	ExportRegistrations(filter domain.ExportFilter) ([]domain.ExportRow, error)
+	GetAdmin(id int) (*domain.Admin, error)
+	ListAdmins() ([]domain.Admin, error)
+	AddAdmin(id int, username string, role domain.AdminRole, addedBy int) error
+	RemoveAdmin(id int) error
+	CheckDatabase(ctx context.Context) error
+	CheckPoster(ctx context.Context) error
+	GetTokensIssuedBy(adminID int) ([]domain.Registration, error)
 	GetUsedTokens() ([]domain.Registration, error)
 	GetUnusedTokens() ([]domain.Registration, error)
+	GetTokensPage(used bool, page int) (*domain.TokenPage, error)
+	GetRegistration(id int) (*domain.Registration, error)
+	LinkForToken(baseURL, token string) (string, error)
 	ValidateAndDecode(encryptedToken string) (string, error)
+	RevokeToken(token, reason string, adminID int) error
+	ListCampaigns() ([]domain.Campaign, error)
+	GetCampaignByCode(code string) (*domain.Campaign, error)
+	CreateCampaign(c *domain.Campaign) error
+	GetTokenCampaign(token string) (*domain.Campaign, error)
+	SetCampaignLanguage(code, language string) error
+	GetStuckJobs() ([]domain.RegistrationJob, error)
+	RetryJob(id int) error
+	TrackLinkOpened(token string)
+	SetNotifications(adminID int, enabled bool) error
+	NotificationsEnabled(adminID int) (bool, error)
+	SetAdminLanguage(adminID int, language string) error
+	AdminLanguage(adminID int) (string, error)
+	GetFunnelStats(days int) ([]domain.FunnelStats, []domain.ReasonCount, error)
 }
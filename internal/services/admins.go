@@ -0,0 +1,92 @@
+package services
+
+import (
+	"certificate/internal/domain"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Назначение владельцев из переменной ADMINS. Срабатывает, только пока в БД
+// нет ни одного владельца: дальше администраторами управляют через бота.
+func (s *RegistrationService) BootstrapOwners(ids []int) error {
+	owners, err := s.admins.CountOwners()
+	if err != nil {
+		return err
+	}
+	if owners > 0 {
+		return nil
+	}
+	if len(ids) == 0 {
+		slog.Warn("В БД нет владельцев бота, а ADMINS не задан: управлять ботом некому")
+		return nil
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := s.admins.SaveAdmin(&domain.Admin{ID: id, Role: domain.RoleOwner, CreatedAt: now}); err != nil {
+			return err
+		}
+	}
+	slog.Info("Назначены владельцы бота из ADMINS", "count", len(ids))
+	return nil
+}
+
+// Получить администратора по ID пользователя Telegram
+func (s *RegistrationService) GetAdmin(id int) (*domain.Admin, error) {
+	return s.admins.GetAdmin(id)
+}
+
+// Список администраторов
+func (s *RegistrationService) ListAdmins() ([]domain.Admin, error) {
+	return s.admins.ListAdmins()
+}
+
+// Добавить администратора или сменить роль существующего.
+// Последнего владельца понизить нельзя.
+func (s *RegistrationService) AddAdmin(id int, username string, role domain.AdminRole, addedBy int) error {
+	if role != domain.RoleOwner {
+		if err := s.checkNotLastOwner(id); err != nil {
+			return err
+		}
+	}
+
+	return s.admins.SaveAdmin(&domain.Admin{
+		ID:        id,
+		Username:  username,
+		Role:      role,
+		AddedBy:   addedBy,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Удалить администратора. Последнего владельца удалить нельзя.
+func (s *RegistrationService) RemoveAdmin(id int) error {
+	if err := s.checkNotLastOwner(id); err != nil {
+		return err
+	}
+	return s.admins.DeleteAdmin(id)
+}
+
+// Ошибка, если администратор — единственный владелец
+func (s *RegistrationService) checkNotLastOwner(id int) error {
+	admin, err := s.admins.GetAdmin(id)
+	if errors.Is(err, domain.ErrAdminNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if admin.Role != domain.RoleOwner {
+		return nil
+	}
+
+	owners, err := s.admins.CountOwners()
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrLastOwner
+	}
+	return nil
+}
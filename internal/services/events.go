@@ -0,0 +1,84 @@
+package services
+
+import (
+	"certificate/internal/domain"
+	"certificate/internal/metrics"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Сколько самых частых причин неудач показывать в статистике
+const statsFailureReasons = 5
+
+// Запись событий воронки. Ошибка записи не должна мешать регистрации, поэтому только логируется.
+func (s *RegistrationService) recordEvent(events ...domain.Event) {
+	now := time.Now()
+	for i := range events {
+		if events[i].CreatedAt.IsZero() {
+			events[i].CreatedAt = now
+		}
+	}
+
+	for _, e := range events {
+		switch e.Type {
+		case domain.EventLinkGenerated:
+			metrics.LinkGenerated()
+		case domain.EventRegistrationFailed:
+			metrics.RegistrationFailed(e.Reason)
+		}
+	}
+
+	if err := s.events.RecordEvents(events); err != nil {
+		slog.Error("Ошибка при записи события воронки", "type", events[0].Type, "error", err)
+	}
+}
+
+// Запись неудачной регистрации с кратким кодом причины
+func (s *RegistrationService) recordFailure(job *domain.RegistrationJob, cause error) {
+	s.recordEvent(domain.Event{
+		Type:       domain.EventRegistrationFailed,
+		Token:      job.Token,
+		CampaignID: job.CampaignID,
+		Reason:     failureReason(cause),
+	})
+}
+
+// Краткий код причины неудачи для группировки в статистике
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrPosterAuth):
+		return "poster_auth"
+	case errors.Is(err, domain.ErrPosterValidation):
+		return "poster_validation"
+	case errors.Is(err, domain.ErrPosterRateLimit):
+		return "poster_rate_limit"
+	case errors.Is(err, domain.ErrPosterServer):
+		return "poster_unavailable"
+	default:
+		return "internal"
+	}
+}
+
+// Статистика воронки за последние days дней (по дням UTC и кампаниям)
+// и самые частые причины неудач за тот же период
+func (s *RegistrationService) GetFunnelStats(days int) ([]domain.FunnelStats, []domain.ReasonCount, error) {
+	if days < 1 || days > domain.MaxStatsDays {
+		days = domain.DefaultStatsDays
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1-days)
+
+	stats, err := s.events.GetFunnelStats(since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reasons, err := s.events.GetFailureReasons(since, statsFailureReasons)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stats, reasons, nil
+}
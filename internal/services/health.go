@@ -0,0 +1,13 @@
+package services
+
+import "context"
+
+// Проверка, что БД открыта и отвечает на чтение
+func (s *RegistrationService) CheckDatabase(ctx context.Context) error {
+	return s.repo.Ping(ctx)
+}
+
+// Проверка, что Poster доступен и принимает токен
+func (s *RegistrationService) CheckPoster(ctx context.Context) error {
+	return s.posterAPI.Ping(ctx)
+}
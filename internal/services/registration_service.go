@@ -3,32 +3,86 @@ package services
 import (
 	"certificate/internal/domain"
 	"certificate/internal/ports"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"time"
 )
 
 type RegistrationService struct {
 	repo      ports.RegistrationRepository
+	campaigns ports.CampaignRepository
+	jobs      ports.RegistrationJobRepository
+	events    ports.EventRepository
+	settings  ports.AdminSettingsRepository
+	exports   ports.ExportRepository
+	admins    ports.AdminRepository
 	posterAPI ports.PosterAPI
+	notifier  ports.Notifier
+	keys      *Keyring
+	tokens    *TokenGenerator
+	linkTTL   time.Duration
+
+	// Задачи, которые обрабатываются прямо сейчас (запросом или фоновым обработчиком)
+	inflight sync.Map
+	// Уведомления, которые отправляются в фоне
+	background sync.WaitGroup
 }
 
-func NewRegistrationService(repo ports.RegistrationRepository, posterAPI ports.PosterAPI) *RegistrationService {
+// Сколько раз пытаемся сгенерировать токен при совпадении с уже существующим
+const maxTokenAttempts = 5
+
+// Сколько последних ссылок показывает /my_links
+const maxIssuedLinks = 50
+
+func NewRegistrationService(
+	repo ports.RegistrationRepository,
+	campaigns ports.CampaignRepository,
+	jobs ports.RegistrationJobRepository,
+	events ports.EventRepository,
+	settings ports.AdminSettingsRepository,
+	exports ports.ExportRepository,
+	admins ports.AdminRepository,
+	posterAPI ports.PosterAPI,
+	keys *Keyring,
+	tokens *TokenGenerator,
+	linkTTL time.Duration,
+) *RegistrationService {
 	return &RegistrationService{
 		repo:      repo,
+		campaigns: campaigns,
+		jobs:      jobs,
+		events:    events,
+		settings:  settings,
+		exports:   exports,
+		admins:    admins,
 		posterAPI: posterAPI,
+		keys:      keys,
+		tokens:    tokens,
+		linkTTL:   linkTTL,
 	}
 }
 
-// Генерация уникальной ссылки на основе времени
-func (s *RegistrationService) GenerateUniqueLink(baseURL string) (string, error) {
-	token := fmt.Sprintf("%d", time.Now().UnixNano())
+// Подключение получателя уведомлений о завершенных регистрациях.
+// Бот создается после сервиса, поэтому подключается отдельно.
+func (s *RegistrationService) SetNotifier(n ports.Notifier) {
+	s.notifier = n
+}
 
-	err := s.repo.Create(token)
+// Генерация уникальной ссылки со случайным токеном
+func (s *RegistrationService) GenerateUniqueLink(baseURL string, opts domain.LinkOptions) (string, error) {
+	reg, err := s.newRegistration(opts)
 	if err != nil {
 		return "", err
 	}
+	if err := s.createWithUniqueToken(reg); err != nil {
+		return "", err
+	}
+	s.recordEvent(domain.Event{Type: domain.EventLinkGenerated, Token: reg.Token, CampaignID: reg.CampaignID})
 
-	encryptedToken, err := encryptToken(token)
+	encryptedToken, err := s.keys.encryptToken(reg.Token)
 	if err != nil {
 		return "", err
 	}
@@ -36,51 +90,225 @@ func (s *RegistrationService) GenerateUniqueLink(baseURL string) (string, error)
 	return baseURL + encryptedToken, nil
 }
 
+// Генерация пачки ссылок в одной транзакции, все записи помечаются меткой пачки
+func (s *RegistrationService) GenerateLinkBatch(baseURL string, count int, opts domain.LinkOptions) ([]domain.Link, error) {
+	if count < 1 || count > domain.MaxLinkBatchSize {
+		return nil, fmt.Errorf("batch size must be between 1 and %d", domain.MaxLinkBatchSize)
+	}
+
+	regs := make([]*domain.Registration, count)
+	for i := range regs {
+		reg, err := s.newRegistration(opts)
+		if err != nil {
+			return nil, err
+		}
+		regs[i] = reg
+	}
+
+	if err := s.createBatchWithUniqueTokens(regs); err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.Event, 0, count)
+	for _, reg := range regs {
+		events = append(events, domain.Event{Type: domain.EventLinkGenerated, Token: reg.Token, CampaignID: reg.CampaignID})
+	}
+	s.recordEvent(events...)
+
+	links := make([]domain.Link, 0, count)
+	for _, reg := range regs {
+		encryptedToken, err := s.keys.encryptToken(reg.Token)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, domain.Link{Token: reg.Token, URL: baseURL + encryptedToken, ExpiresAt: reg.ExpiresAt})
+	}
+
+	return links, nil
+}
+
+// Новая регистрация без токена. Если срок действия или кампания не заданы,
+// используются значения по умолчанию.
+func (s *RegistrationService) newRegistration(opts domain.LinkOptions) (*domain.Registration, error) {
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = s.linkTTL
+	}
+
+	campaignID := domain.DefaultCampaignID
+	if opts.CampaignCode != "" {
+		campaign, err := s.campaigns.GetCampaignByCode(opts.CampaignCode)
+		if err != nil {
+			return nil, err
+		}
+		campaignID = campaign.ID
+	}
+
+	now := time.Now()
+	reg := &domain.Registration{CreatedAt: now, BatchLabel: opts.BatchLabel, CampaignID: campaignID,
+		IssuedBy: opts.IssuedBy, IssuedByUsername: opts.IssuedByUsername}
+	if ttl > 0 {
+		reg.ExpiresAt = now.Add(ttl)
+	}
+	return reg, nil
+}
+
+// Сохранение регистрации с новым токеном, при коллизии токен генерируется заново
+func (s *RegistrationService) createWithUniqueToken(reg *domain.Registration) error {
+	for attempt := 1; attempt <= maxTokenAttempts; attempt++ {
+		token, err := s.tokens.Generate()
+		if err != nil {
+			return fmt.Errorf("failed to generate token: %w", err)
+		}
+		reg.Token = token
+
+		err = s.repo.Create(reg)
+		if errors.Is(err, domain.ErrTokenExists) {
+			slog.Warn("Сгенерирован уже существующий токен, повторяем", "attempt", attempt)
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed to generate unique token after %d attempts", maxTokenAttempts)
+}
+
+// Сохранение пачки регистраций, при коллизии все токены пачки генерируются заново
+func (s *RegistrationService) createBatchWithUniqueTokens(regs []*domain.Registration) error {
+	for attempt := 1; attempt <= maxTokenAttempts; attempt++ {
+		for _, reg := range regs {
+			token, err := s.tokens.Generate()
+			if err != nil {
+				return fmt.Errorf("failed to generate token: %w", err)
+			}
+			reg.Token = token
+		}
+
+		err := s.repo.CreateBatch(regs)
+		if errors.Is(err, domain.ErrTokenExists) {
+			slog.Warn("В пачке сгенерирован уже существующий токен, повторяем", "attempt", attempt)
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed to generate unique tokens after %d attempts", maxTokenAttempts)
+}
+
 // Проверка, был ли уже использован токен
 func (s *RegistrationService) ValidateAndDecode(encryptedToken string) (string, error) {
-	decodedToken, err := decryptToken(encryptedToken)
+	decodedToken, err := s.keys.decryptToken(encryptedToken)
 	if err != nil {
 		return "", fmt.Errorf("invalid token: %w", err)
 	}
 
-	// Проверяем, что токен существует и не был использован
+	// Проверяем, что токен существует, не был использован и не истёк
 	reg, err := s.repo.GetByToken(decodedToken)
-	if err != nil || reg.Used {
-		return "", fmt.Errorf("invalid or used token")
+	if errors.Is(err, domain.ErrTokenNotFound) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get token: %w", err)
+	}
+	if reg.Revoked {
+		return "", domain.ErrTokenRevoked
+	}
+	if reg.Used {
+		return "", domain.ErrTokenUsed
+	}
+	if reg.Expired(time.Now()) {
+		return "", domain.ErrTokenExpired
 	}
 
 	return decodedToken, nil
 }
 
-// Пометить токен как использованный
-func (s *RegistrationService) MarkTokenUsed(token string, client domain.Client) error {
-	return s.repo.MarkTokenUsed(token, client.Name, client.Phone)
-}
-
-// Выполнить полную регистрацию клиента
-func (s *RegistrationService) RegisterUser(token, name, phone, birthday string) error {
-	client := domain.Client{
-		Name:     name,
-		Phone:    phone,
-		Birthday: birthday,
+// Выполнить полную регистрацию клиента по условиям кампании, к которой привязан токен.
+// Токен захватывается и задача записывается в БД одной транзакцией, после чего
+// регистрация сразу проводится в Poster. Если довести ее до конца не удалось,
+// возвращается кампания и ErrRegistrationPending: задачу доведет фоновый обработчик
+// или администратор.
+func (s *RegistrationService) RegisterUser(token, name, phone, birthday string) (*domain.Campaign, error) {
+	reg, err := s.repo.GetByToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
 
-	clientID, err := s.posterAPI.CreateClient(client)
+	campaign, err := s.campaigns.GetCampaign(reg.CampaignID)
 	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
 	}
 
-	// Начисляем бонусы
-	if err := s.posterAPI.ChangeClientBonus(clientID); err != nil {
-		return fmt.Errorf("failed to change client bonus: %w", err)
+	now := time.Now()
+	job := &domain.RegistrationJob{
+		Token:      token,
+		Name:       name,
+		Phone:      phone,
+		Birthday:   birthday,
+		CampaignID: campaign.ID,
+		State:      domain.JobPending,
+		// Пока задачу проводит этот запрос, фоновый обработчик ее не берет
+		NextAttemptAt: now.Add(jobLease),
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
-	// Помечаем токен как использованный
-	if err := s.MarkTokenUsed(token, client); err != nil {
-		return fmt.Errorf("failed to mark token as used: %w", err)
+	if err := s.jobs.EnqueueRegistration(job); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) || errors.Is(err, domain.ErrTokenRevoked) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to enqueue registration: %w", err)
 	}
+	s.recordEvent(domain.Event{Type: domain.EventFormSubmitted, Token: token, CampaignID: campaign.ID})
 
-	return nil
+	err = s.processJob(job)
+	switch {
+	case err == nil:
+		return campaign, nil
+	case errors.Is(err, domain.ErrPosterValidation) && job.State == domain.JobPending:
+		// Poster отклонил данные клиента, и в Poster ничего не создано:
+		// отменяем задачу, чтобы клиент мог исправить данные и отправить форму еще раз
+		if cancelErr := s.jobs.CancelJob(job); cancelErr != nil {
+			slog.Error("Не удалось отменить задачу регистрации", "jobID", job.ID, "error", cancelErr)
+		}
+		return nil, err
+	default:
+		slog.Warn("Регистрация отложена до следующей попытки", "jobID", job.ID, "error", err)
+		return campaign, domain.ErrRegistrationPending
+	}
+}
+
+// Отметить, что клиент открыл форму по действующей ссылке
+func (s *RegistrationService) TrackLinkOpened(token string) {
+	reg, err := s.repo.GetByToken(token)
+	if err != nil {
+		slog.Warn("Не удалось записать открытие ссылки", "error", err)
+		return
+	}
+	s.recordEvent(domain.Event{Type: domain.EventLinkOpened, Token: token, CampaignID: reg.CampaignID})
+}
+
+// Включить или отключить уведомления о регистрациях для администратора
+func (s *RegistrationService) SetNotifications(adminID int, enabled bool) error {
+	return s.settings.SetNotifications(adminID, enabled)
+}
+
+// Получает ли администратор уведомления о регистрациях
+func (s *RegistrationService) NotificationsEnabled(adminID int) (bool, error) {
+	return s.settings.NotificationsEnabled(adminID)
+}
+
+// Сохранить язык ответов бота для администратора
+func (s *RegistrationService) SetAdminLanguage(adminID int, language string) error {
+	return s.settings.SetLanguage(adminID, language)
+}
+
+// Язык ответов бота, выбранный администратором
+func (s *RegistrationService) AdminLanguage(adminID int) (string, error) {
+	return s.settings.Language(adminID)
+}
+
+// Отозвать неиспользованный токен
+func (s *RegistrationService) RevokeToken(token, reason string, adminID int) error {
+	return s.repo.RevokeToken(token, reason, adminID, time.Now())
 }
 
 // Получить информацию пользователя, который использовал токен
@@ -93,6 +321,126 @@ func (s *RegistrationService) GetTokenUsage(token string) (*domain.TokenUsage, e
 	return usage, nil
 }
 
+// Получить сведения о ссылке: статус, кампанию, кто выдал и кто зарегистрировался
+func (s *RegistrationService) GetTokenDetails(token string) (*domain.TokenDetails, error) {
+	reg, err := s.repo.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &domain.TokenDetails{Registration: *reg}
+	if campaign, err := s.campaigns.GetCampaign(reg.CampaignID); err == nil {
+		details.CampaignCode = campaign.Code
+	}
+
+	// Запись об использовании появляется только после завершения регистрации в Poster
+	if reg.Used {
+		if usage, err := s.repo.GetTokenUsage(token); err == nil {
+			details.Usage = usage
+		}
+	}
+
+	return details, nil
+}
+
+// Получить последние ссылки, выданные администратором
+func (s *RegistrationService) GetTokensIssuedBy(adminID int) ([]domain.Registration, error) {
+	return s.repo.GetTokensIssuedBy(adminID, maxIssuedLinks)
+}
+
+// Получить страницу использованных или действующих токенов.
+// Номер страницы за пределами списка приводится к первой или последней странице.
+func (s *RegistrationService) GetTokensPage(used bool, page int) (*domain.TokenPage, error) {
+	page = max(page, 1)
+	tokens, total, err := s.repo.GetTokensPage(used, (page-1)*domain.TokensPageSize, domain.TokensPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := max((total+domain.TokensPageSize-1)/domain.TokensPageSize, 1)
+	if page > pages {
+		page = pages
+		tokens, total, err = s.repo.GetTokensPage(used, (page-1)*domain.TokensPageSize, domain.TokensPageSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.TokenPage{Tokens: tokens, Page: page, Pages: pages, Total: total}, nil
+}
+
+// Получить токен по идентификатору записи
+func (s *RegistrationService) GetRegistration(id int) (*domain.Registration, error) {
+	return s.repo.GetByID(id)
+}
+
+// Ссылка на регистрацию для действующего токена, например чтобы отправить QR-код повторно
+func (s *RegistrationService) LinkForToken(baseURL, token string) (string, error) {
+	reg, err := s.repo.GetByToken(token)
+	if err != nil {
+		return "", err
+	}
+	switch reg.Status(time.Now()) {
+	case domain.StatusUsed:
+		return "", domain.ErrTokenUsed
+	case domain.StatusRevoked:
+		return "", domain.ErrTokenRevoked
+	case domain.StatusExpired:
+		return "", domain.ErrTokenExpired
+	}
+
+	encryptedToken, err := s.keys.encryptToken(token)
+	if err != nil {
+		return "", err
+	}
+	return baseURL + encryptedToken, nil
+}
+
+// Найти регистрации по номеру телефона в формате E.164
+func (s *RegistrationService) FindRegistrationsByPhone(phone string) ([]domain.RegistrationMatch, error) {
+	return s.repo.FindUsageByPhone(phone, domain.MaxSearchResults)
+}
+
+// Найти регистрации по части имени
+func (s *RegistrationService) FindRegistrationsByName(name string) ([]domain.RegistrationMatch, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	return s.repo.FindUsageByName(name, domain.MaxSearchResults)
+}
+
+// Выгрузка завершенных регистраций. Границы периода берутся целыми днями
+// в локальном часовом поясе: From с начала дня, To до конца дня.
+func (s *RegistrationService) ExportRegistrations(filter domain.ExportFilter) ([]domain.ExportRow, error) {
+	var campaignID int
+	if filter.CampaignCode != "" {
+		campaign, err := s.campaigns.GetCampaignByCode(filter.CampaignCode)
+		if err != nil {
+			return nil, err
+		}
+		campaignID = campaign.ID
+	}
+
+	var from, to time.Time
+	if !filter.From.IsZero() {
+		from = startOfDay(filter.From)
+	}
+	if !filter.To.IsZero() {
+		to = startOfDay(filter.To).AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, fmt.Errorf("export period start must not be after its end")
+	}
+
+	return s.exports.GetRegistrationsForExport(from, to, campaignID)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
 // Получить список использованных токенов
 func (s *RegistrationService) GetUsedTokens() ([]domain.Registration, error) {
 	return s.repo.GetUsedTokens()
@@ -102,3 +450,41 @@ func (s *RegistrationService) GetUsedTokens() ([]domain.Registration, error) {
 func (s *RegistrationService) GetUnusedTokens() ([]domain.Registration, error) {
 	return s.repo.GetUnusedTokens()
 }
+
+// Получить список кампаний
+func (s *RegistrationService) ListCampaigns() ([]domain.Campaign, error) {
+	return s.campaigns.ListCampaigns()
+}
+
+// Получить кампанию по коду
+func (s *RegistrationService) GetCampaignByCode(code string) (*domain.Campaign, error) {
+	return s.campaigns.GetCampaignByCode(code)
+}
+
+// Получить кампанию, к которой привязан токен
+func (s *RegistrationService) GetTokenCampaign(token string) (*domain.Campaign, error) {
+	reg, err := s.repo.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return s.campaigns.GetCampaign(reg.CampaignID)
+}
+
+// Сменить язык страниц регистрации кампании, пустая строка — по браузеру клиента
+func (s *RegistrationService) SetCampaignLanguage(code, language string) error {
+	campaign, err := s.campaigns.GetCampaignByCode(code)
+	if err != nil {
+		return err
+	}
+	return s.campaigns.UpdateCampaignLanguage(campaign.ID, language)
+}
+
+// Создать новую кампанию
+func (s *RegistrationService) CreateCampaign(c *domain.Campaign) error {
+	if c.Code == "" || c.BonusAmount <= 0 || c.ClientGroupID <= 0 {
+		return fmt.Errorf("campaign code, bonus amount and client group are required")
+	}
+
+	c.CreatedAt = time.Now()
+	return s.campaigns.CreateCampaign(c)
+}
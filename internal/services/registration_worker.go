@@ -0,0 +1,261 @@
+package services
+
+import (
+	"certificate/internal/domain"
+	"certificate/internal/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// Как часто фоновый обработчик ищет задачи для повторной попытки
+	workerInterval = 30 * time.Second
+	// Сколько задач обрабатывается за один проход
+	workerBatchSize = 20
+	// После стольких неудачных попыток задача помечается как требующая помощи администратора
+	maxJobAttempts = 10
+	// Первая задержка перед повтором, дальше она удваивается до maxRetryDelay
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = time.Hour
+	// На это время задача закрепляется за тем, кто ее обрабатывает. Если процесс
+	// упадет посреди обработки, фоновый обработчик возьмет задачу после истечения срока.
+	jobLease = 5 * time.Minute
+)
+
+// RegistrationWorker периодически доводит до конца отложенные регистрации
+type RegistrationWorker struct {
+	svc *RegistrationService
+}
+
+func NewRegistrationWorker(svc *RegistrationService) *RegistrationWorker {
+	return &RegistrationWorker{svc: svc}
+}
+
+// Запуск обработчика, работает до отмены контекста
+func (w *RegistrationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(workerInterval)
+	defer ticker.Stop()
+
+	for {
+		w.svc.ProcessDueJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Обработка всех задач, время повторной попытки которых наступило.
+// При отмене контекста начатая задача доводится до конца, остальные ждут следующего запуска.
+func (s *RegistrationService) ProcessDueJobs(ctx context.Context) {
+	jobs, err := s.jobs.GetDueJobs(time.Now(), workerBatchSize)
+	if err != nil {
+		slog.Error("Ошибка при получении задач регистрации", "error", err)
+		return
+	}
+
+	for i := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		err := s.processJob(&jobs[i])
+		if errors.Is(err, domain.ErrJobBusy) {
+			continue
+		}
+		if err != nil {
+			slog.Warn("Не удалось завершить регистрацию", "jobID", jobs[i].ID, "attempts", jobs[i].Attempts, "error", err)
+		}
+	}
+}
+
+// Проведение задачи по шагам начиная с того, на котором она остановилась.
+// Каждый шаг сохраняется в БД, поэтому при повторе уже выполненные вызовы Poster не повторяются.
+// Перед началом задача захватывается в БД: устаревшая копия (например, прочитанная
+// фоновым обработчиком, пока задачу доводил запрос) не пройдет захват, и ErrJobBusy
+// не даст второй раз создать клиента или начислить бонусы.
+func (s *RegistrationService) processJob(job *domain.RegistrationJob) error {
+	if _, busy := s.inflight.LoadOrStore(job.ID, struct{}{}); busy {
+		return domain.ErrJobBusy
+	}
+	defer s.inflight.Delete(job.ID)
+
+	now := time.Now()
+	if err := s.jobs.ClaimJob(job, now, now.Add(jobLease)); err != nil {
+		return err
+	}
+
+	for job.State != domain.JobDone {
+		if err := s.runJobStep(job); err != nil {
+			s.scheduleRetry(job, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Выполнение одного шага задачи
+func (s *RegistrationService) runJobStep(job *domain.RegistrationJob) error {
+	campaign, err := s.campaigns.GetCampaign(job.CampaignID)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	switch job.State {
+	case domain.JobPending:
+		clientID, err := s.posterAPI.FindClientByPhone(job.Phone)
+		if err != nil {
+			return fmt.Errorf("failed to find client: %w", err)
+		}
+
+		event := domain.EventClientFound
+		if clientID == 0 {
+			client := domain.Client{
+				Name:     job.Name,
+				Phone:    job.Phone,
+				Birthday: job.Birthday,
+				GroupID:  campaign.ClientGroupID,
+			}
+
+			clientID, err = s.posterAPI.CreateClient(client)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			event = domain.EventClientCreated
+		}
+		job.ClientID = clientID
+		job.ClientExisted = event == domain.EventClientFound
+		job.State = domain.JobClientCreated
+		s.recordEvent(domain.Event{Type: event, Token: job.Token, CampaignID: job.CampaignID})
+
+	case domain.JobClientCreated:
+		if err := s.posterAPI.ChangeClientBonus(job.ClientID, campaign.BonusAmount); err != nil {
+			return fmt.Errorf("failed to change client bonus: %w", err)
+		}
+		job.State = domain.JobBonusAwarded
+		s.recordEvent(domain.Event{Type: domain.EventBonusAwarded, Token: job.Token, CampaignID: job.CampaignID})
+
+	case domain.JobBonusAwarded:
+		job.State = domain.JobDone
+		job.UpdatedAt = time.Now()
+		if err := s.jobs.CompleteJob(job); err != nil {
+			job.State = domain.JobBonusAwarded
+			return fmt.Errorf("failed to mark token as used: %w", err)
+		}
+		slog.Info("Регистрация завершена", "jobID", job.ID, "clientID", job.ClientID)
+		metrics.RegistrationSucceeded()
+		s.notifyCompleted(job, campaign)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown job state %q", job.State)
+	}
+
+	job.UpdatedAt = time.Now()
+	if err := s.jobs.UpdateJob(job); err != nil {
+		return fmt.Errorf("failed to save job state: %w", err)
+	}
+	return nil
+}
+
+// Уведомление администраторов о завершенной регистрации. Отправка идет
+// в фоне, чтобы ответ клиенту не ждал Telegram.
+func (s *RegistrationService) notifyCompleted(job *domain.RegistrationJob, campaign *domain.Campaign) {
+	if s.notifier == nil {
+		return
+	}
+
+	notice := domain.RegistrationNotice{
+		Token:         job.Token,
+		Name:          job.Name,
+		Phone:         job.Phone,
+		ClientID:      job.ClientID,
+		ClientExisted: job.ClientExisted,
+		CampaignCode:  campaign.Code,
+	}
+	if reg, err := s.repo.GetByToken(job.Token); err == nil {
+		notice.IssuedBy = reg.IssuedBy
+		notice.IssuedByUsername = reg.IssuedByUsername
+	}
+
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		s.notifier.NotifyRegistration(notice)
+	}()
+}
+
+// Ожидание фоновых отправок уведомлений, например перед остановкой
+func (s *RegistrationService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Планирование повторной попытки с экспоненциальной задержкой
+func (s *RegistrationService) scheduleRetry(job *domain.RegistrationJob, cause error) {
+	now := time.Now()
+	job.Attempts++
+	job.LastError = cause.Error()
+	job.UpdatedAt = now
+	job.NextAttemptAt = now.Add(retryDelay(job.Attempts))
+	if job.Attempts >= maxJobAttempts || !retryable(cause) {
+		job.Failed = true
+		slog.Error("Регистрация требует вмешательства администратора", "jobID", job.ID, "error", cause)
+		s.recordFailure(job, cause)
+	}
+
+	if err := s.jobs.UpdateJob(job); err != nil {
+		slog.Error("Не удалось сохранить состояние задачи", "jobID", job.ID, "error", err)
+	}
+}
+
+// Повторять имеет смысл только временные ошибки: недоступность Poster и превышение лимита.
+// Отказ в авторизации и отклоненные данные сами не исправятся.
+func retryable(err error) bool {
+	return !errors.Is(err, domain.ErrPosterAuth) && !errors.Is(err, domain.ErrPosterValidation)
+}
+
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Получить список зависших задач
+func (s *RegistrationService) GetStuckJobs() ([]domain.RegistrationJob, error) {
+	return s.jobs.GetStuckJobs()
+}
+
+// Повторно запустить задачу, не дожидаясь следующей попытки. Счетчик попыток
+// сбрасывается при захвате задачи, поэтому если ее в это время обрабатывает
+// фоновый обработчик, возвращается ErrJobBusy и задача не меняется.
+func (s *RegistrationService) RetryJob(id int) error {
+	job, err := s.jobs.GetJob(id)
+	if err != nil {
+		return err
+	}
+	if job.State == domain.JobDone {
+		return nil
+	}
+
+	job.Failed = false
+	job.Attempts = 0
+	return s.processJob(job)
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"certificate/internal/domain"
+	"certificate/internal/ports"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Задачи в памяти. Захват повторяет условие SQLite: шаг и время обновления
+// должны совпадать с сохраненными.
+type fakeJobs struct {
+	ports.RegistrationJobRepository
+	jobs map[int]domain.RegistrationJob
+	// Сколько раз задача была завершена (запись в token_usage)
+	completed map[int]int
+	// Ошибка следующего вызова CompleteJob
+	completeErr error
+}
+
+func newFakeJobs(jobs ...domain.RegistrationJob) *fakeJobs {
+	f := &fakeJobs{jobs: make(map[int]domain.RegistrationJob), completed: make(map[int]int)}
+	for _, job := range jobs {
+		f.jobs[job.ID] = job
+	}
+	return f
+}
+
+func (f *fakeJobs) ClaimJob(job *domain.RegistrationJob, now, leaseUntil time.Time) error {
+	stored, ok := f.jobs[job.ID]
+	if !ok || stored.State != job.State || !stored.UpdatedAt.Equal(job.UpdatedAt) {
+		return domain.ErrJobBusy
+	}
+	job.NextAttemptAt = leaseUntil
+	job.UpdatedAt = now
+	stored.Failed, stored.Attempts, stored.NextAttemptAt, stored.UpdatedAt = job.Failed, job.Attempts, leaseUntil, now
+	f.jobs[job.ID] = stored
+	return nil
+}
+
+func (f *fakeJobs) UpdateJob(job *domain.RegistrationJob) error {
+	f.jobs[job.ID] = *job
+	return nil
+}
+
+func (f *fakeJobs) CompleteJob(job *domain.RegistrationJob) error {
+	if err := f.completeErr; err != nil {
+		f.completeErr = nil
+		return err
+	}
+	if f.completed[job.ID] > 0 {
+		return errors.New("UNIQUE constraint failed: token_usage.token")
+	}
+	f.completed[job.ID]++
+	f.jobs[job.ID] = *job
+	return nil
+}
+
+func (f *fakeJobs) GetJob(id int) (*domain.RegistrationJob, error) {
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, domain.ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (f *fakeJobs) GetDueJobs(now time.Time, limit int) ([]domain.RegistrationJob, error) {
+	var due []domain.RegistrationJob
+	for _, job := range f.jobs {
+		if job.State != domain.JobDone && !job.Failed && !job.NextAttemptAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	return due, nil
+}
+
+// Poster с подсчетом вызовов. Ошибки из очередей возвращаются по одной на вызов.
+type fakePoster struct {
+	ports.PosterAPI
+	existingClientID int
+	finds, creates   int
+	bonuses          int
+	findErrs         []error
+	createErrs       []error
+	bonusErrs        []error
+}
+
+func popErr(errs *[]error) error {
+	if len(*errs) == 0 {
+		return nil
+	}
+	err := (*errs)[0]
+	*errs = (*errs)[1:]
+	return err
+}
+
+func (p *fakePoster) FindClientByPhone(phone string) (int, error) {
+	p.finds++
+	if err := popErr(&p.findErrs); err != nil {
+		return 0, err
+	}
+	return p.existingClientID, nil
+}
+
+func (p *fakePoster) CreateClient(c domain.Client) (int, error) {
+	p.creates++
+	if err := popErr(&p.createErrs); err != nil {
+		return 0, err
+	}
+	return 42, nil
+}
+
+func (p *fakePoster) ChangeClientBonus(clientID, amount int) error {
+	p.bonuses++
+	return popErr(&p.bonusErrs)
+}
+
+type fakeCampaigns struct {
+	ports.CampaignRepository
+}
+
+func (fakeCampaigns) GetCampaign(id int) (*domain.Campaign, error) {
+	return &domain.Campaign{ID: id, Code: "default", BonusAmount: 1000, ClientGroupID: 2}, nil
+}
+
+type fakeEvents struct {
+	ports.EventRepository
+}
+
+func (fakeEvents) RecordEvents(events []domain.Event) error {
+	return nil
+}
+
+func newTestService(jobs *fakeJobs, poster *fakePoster) *RegistrationService {
+	return NewRegistrationService(nil, fakeCampaigns{}, jobs, fakeEvents{}, nil, nil, nil, poster, nil, nil, 0)
+}
+
+func testJob(state domain.JobState) domain.RegistrationJob {
+	created := time.Now().Add(-time.Minute)
+	job := domain.RegistrationJob{
+		ID: 1, Token: "token", Name: "Иван", Phone: "+77771234567", CampaignID: domain.DefaultCampaignID,
+		State: state, NextAttemptAt: created, CreatedAt: created, UpdatedAt: created,
+	}
+	if state != domain.JobPending {
+		job.ClientID = 42
+	}
+	return job
+}
+
+func TestProcessJobResumesFromEachStep(t *testing.T) {
+	tests := []struct {
+		state                   domain.JobState
+		finds, creates, bonuses int
+	}{
+		{domain.JobPending, 1, 1, 1},
+		{domain.JobClientCreated, 0, 0, 1},
+		{domain.JobBonusAwarded, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			jobs := newFakeJobs(testJob(tt.state))
+			poster := &fakePoster{}
+			svc := newTestService(jobs, poster)
+
+			job, _ := jobs.GetJob(1)
+			if err := svc.processJob(job); err != nil {
+				t.Fatalf("processJob: %v", err)
+			}
+
+			if poster.finds != tt.finds || poster.creates != tt.creates || poster.bonuses != tt.bonuses {
+				t.Errorf("calls find/create/bonus = %d/%d/%d, want %d/%d/%d",
+					poster.finds, poster.creates, poster.bonuses, tt.finds, tt.creates, tt.bonuses)
+			}
+			if got := jobs.jobs[1].State; got != domain.JobDone {
+				t.Errorf("state = %s, want %s", got, domain.JobDone)
+			}
+			if jobs.completed[1] != 1 {
+				t.Errorf("completed %d times, want 1", jobs.completed[1])
+			}
+		})
+	}
+}
+
+func TestProcessJobExistingClientIsNotCreated(t *testing.T) {
+	jobs := newFakeJobs(testJob(domain.JobPending))
+	poster := &fakePoster{existingClientID: 7}
+	svc := newTestService(jobs, poster)
+
+	job, _ := jobs.GetJob(1)
+	if err := svc.processJob(job); err != nil {
+		t.Fatalf("processJob: %v", err)
+	}
+
+	stored := jobs.jobs[1]
+	if poster.creates != 0 || stored.ClientID != 7 || !stored.ClientExisted {
+		t.Errorf("creates = %d, client = %d, existed = %v; want 0, 7, true", poster.creates, stored.ClientID, stored.ClientExisted)
+	}
+}
+
+func TestProcessJobRetriesOnlyFailedStep(t *testing.T) {
+	jobs := newFakeJobs(testJob(domain.JobPending))
+	poster := &fakePoster{bonusErrs: []error{domain.ErrPosterServer}}
+	svc := newTestService(jobs, poster)
+
+	job, _ := jobs.GetJob(1)
+	if err := svc.processJob(job); !errors.Is(err, domain.ErrPosterServer) {
+		t.Fatalf("processJob error = %v, want %v", err, domain.ErrPosterServer)
+	}
+
+	stored := jobs.jobs[1]
+	if stored.State != domain.JobClientCreated || stored.Attempts != 1 || stored.Failed {
+		t.Fatalf("after failure state = %s, attempts = %d, failed = %v", stored.State, stored.Attempts, stored.Failed)
+	}
+	if delay := time.Until(stored.NextAttemptAt); delay <= 0 || delay > baseRetryDelay {
+		t.Errorf("next attempt in %v, want up to %v", delay, baseRetryDelay)
+	}
+
+	job, _ = jobs.GetJob(1)
+	if err := svc.processJob(job); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if poster.finds != 1 || poster.creates != 1 || poster.bonuses != 2 {
+		t.Errorf("calls find/create/bonus = %d/%d/%d, want 1/1/2", poster.finds, poster.creates, poster.bonuses)
+	}
+}
+
+func TestProcessJobNoSecondBonusAfterBonusAwarded(t *testing.T) {
+	jobs := newFakeJobs(testJob(domain.JobClientCreated))
+	jobs.completeErr = errors.New("database is locked")
+	poster := &fakePoster{}
+	svc := newTestService(jobs, poster)
+
+	job, _ := jobs.GetJob(1)
+	if err := svc.processJob(job); err == nil {
+		t.Fatal("processJob succeeded, want completion error")
+	}
+	if got := jobs.jobs[1].State; got != domain.JobBonusAwarded {
+		t.Fatalf("state = %s, want %s", got, domain.JobBonusAwarded)
+	}
+
+	job, _ = jobs.GetJob(1)
+	if err := svc.processJob(job); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if poster.bonuses != 1 {
+		t.Errorf("ChangeClientBonus called %d times, want 1", poster.bonuses)
+	}
+	if jobs.completed[1] != 1 {
+		t.Errorf("completed %d times, want 1", jobs.completed[1])
+	}
+}
+
+// Фоновый обработчик прочитал задачу, пока ее доводил запрос: устаревшая копия
+// не должна пройти шаги еще раз
+func TestProcessJobSkipsStaleCopy(t *testing.T) {
+	jobs := newFakeJobs(testJob(domain.JobPending))
+	poster := &fakePoster{}
+	svc := newTestService(jobs, poster)
+
+	due, _ := jobs.GetDueJobs(time.Now(), workerBatchSize)
+	stale := due[0]
+
+	job, _ := jobs.GetJob(1)
+	if err := svc.processJob(job); err != nil {
+		t.Fatalf("processJob: %v", err)
+	}
+
+	if err := svc.processJob(&stale); !errors.Is(err, domain.ErrJobBusy) {
+		t.Fatalf("stale copy error = %v, want %v", err, domain.ErrJobBusy)
+	}
+	if poster.finds != 1 || poster.bonuses != 1 || jobs.completed[1] != 1 {
+		t.Errorf("find/bonus/completed = %d/%d/%d, want 1/1/1", poster.finds, poster.bonuses, jobs.completed[1])
+	}
+	if got := jobs.jobs[1]; got.State != domain.JobDone || got.Failed {
+		t.Errorf("state = %s, failed = %v; want done, not failed", got.State, got.Failed)
+	}
+}
+
+func TestRetryJobSkipsJobInProgress(t *testing.T) {
+	jobs := newFakeJobs(testJob(domain.JobPending))
+	poster := &fakePoster{}
+	svc := newTestService(jobs, poster)
+
+	svc.inflight.Store(1, struct{}{})
+	if err := svc.RetryJob(1); !errors.Is(err, domain.ErrJobBusy) {
+		t.Fatalf("RetryJob error = %v, want %v", err, domain.ErrJobBusy)
+	}
+	if poster.finds != 0 {
+		t.Errorf("FindClientByPhone called %d times, want 0", poster.finds)
+	}
+}
+
+func TestProcessJobFailureMarksJob(t *testing.T) {
+	tests := []struct {
+		err      error
+		attempts int // попыток до текущей
+		failed   bool
+	}{
+		{domain.ErrPosterAuth, 0, true},
+		{domain.ErrPosterValidation, 0, true},
+		{domain.ErrPosterServer, 0, false},
+		{domain.ErrPosterRateLimit, 0, false},
+		{domain.ErrPosterServer, maxJobAttempts - 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v after %d", tt.err, tt.attempts), func(t *testing.T) {
+			start := testJob(domain.JobPending)
+			start.Attempts = tt.attempts
+			jobs := newFakeJobs(start)
+			poster := &fakePoster{findErrs: []error{fmt.Errorf("poster: %w", tt.err)}}
+			svc := newTestService(jobs, poster)
+
+			job, _ := jobs.GetJob(1)
+			if err := svc.processJob(job); !errors.Is(err, tt.err) {
+				t.Fatalf("processJob error = %v, want %v", err, tt.err)
+			}
+
+			stored := jobs.jobs[1]
+			if stored.Failed != tt.failed || stored.Attempts != tt.attempts+1 {
+				t.Errorf("failed = %v, attempts = %d; want %v, %d", stored.Failed, stored.Attempts, tt.failed, tt.attempts+1)
+			}
+			if stored.State != domain.JobPending || stored.LastError == "" {
+				t.Errorf("state = %s, last error = %q", stored.State, stored.LastError)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{maxJobAttempts, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{domain.ErrPosterAuth, false},
+		{fmt.Errorf("failed to create client: %w", domain.ErrPosterValidation), false},
+		{domain.ErrPosterRateLimit, true},
+		{domain.ErrPosterServer, true},
+		{errors.New("connection reset"), true},
+	}
+
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const (
+	minTokenLength = 8
+	maxTokenLength = 64
+)
+
+// TokenGenerator выдает криптографически случайные токены
+// заданной длины из заданного алфавита.
+type TokenGenerator struct {
+	length   int
+	alphabet []rune
+}
+
+func NewTokenGenerator(length int, alphabet string) (*TokenGenerator, error) {
+	if length < minTokenLength || length > maxTokenLength {
+		return nil, fmt.Errorf("token length must be between %d and %d", minTokenLength, maxTokenLength)
+	}
+
+	runes := []rune(alphabet)
+	seen := make(map[rune]struct{}, len(runes))
+	for _, r := range runes {
+		if _, dup := seen[r]; dup {
+			return nil, fmt.Errorf("token alphabet contains duplicate character %q", r)
+		}
+		seen[r] = struct{}{}
+	}
+	if len(runes) < 2 {
+		return nil, fmt.Errorf("token alphabet must contain at least 2 characters")
+	}
+
+	return &TokenGenerator{length: length, alphabet: runes}, nil
+}
+
+// Новый случайный токен
+func (g *TokenGenerator) Generate() (string, error) {
+	max := big.NewInt(int64(len(g.alphabet)))
+	token := make([]rune, g.length)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		token[i] = g.alphabet[n.Int64()]
+	}
+	return string(token), nil
+}
@@ -6,57 +6,104 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 )
 
-var encryptionKey = []byte("A1b2C3d4E5f6G7h8I9j0K1l2M3n4O5p6")
+// Разделитель идентификатора ключа и шифртекста в токене: "<keyID>.<base64>"
+const keyIDSeparator = "."
 
-func decryptToken(encryptedToken string) (string, error) {
-	data, err := base64.URLEncoding.DecodeString(encryptedToken)
-	if err != nil {
-		return "", err
+// Keyring — набор ключей шифрования токенов.
+// Новые токены шифруются текущим ключом, а старые ключи используются
+// только для расшифровки ссылок, выданных до ротации.
+type Keyring struct {
+	currentID string
+	ciphers   map[string]cipher.AEAD
+}
+
+// NewKeyring создает набор ключей. keys должен содержать ключ currentID.
+func NewKeyring(currentID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("current key %q is not configured", currentID)
 	}
 
-	block, err := aes.NewCipher(encryptionKey)
-	if err != nil {
-		return "", err
+	kr := &Keyring{currentID: currentID, ciphers: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, keyIDSeparator) {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		aesGCM, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		kr.ciphers[id] = aesGCM
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
+	return kr, nil
+}
+
+func (k *Keyring) decryptToken(encryptedToken string) (string, error) {
+	keyID, payload, found := strings.Cut(encryptedToken, keyIDSeparator)
+	if !found {
+		// Токены без идентификатора выпущены до ротации ключей,
+		// пробуем расшифровать их каждым из доступных ключей
+		return k.decryptLegacy(encryptedToken)
 	}
 
-	nonceSize := aesGCM.NonceSize()
-	if len(data) < nonceSize {
-		return "", errors.New("invalid encrypted token")
+	aesGCM, ok := k.ciphers[keyID]
+	if !ok {
+		return "", fmt.Errorf("unknown key id %q", keyID)
 	}
 
-	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
+	return open(aesGCM, payload, []byte(keyID))
+}
+
+func (k *Keyring) decryptLegacy(encryptedToken string) (string, error) {
+	for _, aesGCM := range k.ciphers {
+		if token, err := open(aesGCM, encryptedToken, nil); err == nil {
+			return token, nil
+		}
+	}
+	return "", errors.New("invalid encrypted token")
+}
+
+func (k *Keyring) encryptToken(token string) (string, error) {
+	aesGCM := k.ciphers[k.currentID]
+
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", err
 	}
 
-	return string(plaintext), nil
+	// Идентификатор ключа передаем как дополнительные данные,
+	// чтобы его нельзя было подменить в готовой ссылке
+	ciphertext := aesGCM.Seal(nonce, nonce, []byte(token), []byte(k.currentID))
+	return k.currentID + keyIDSeparator + base64.URLEncoding.EncodeToString(ciphertext), nil
 }
 
-func encryptToken(token string) (string, error) {
-	block, err := aes.NewCipher(encryptionKey)
+func open(aesGCM cipher.AEAD, payload string, additionalData []byte) (string, error) {
+	data, err := base64.URLEncoding.DecodeString(payload)
 	if err != nil {
 		return "", err
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
+	nonceSize := aesGCM.NonceSize()
+	if len(data) < nonceSize {
+		return "", errors.New("invalid encrypted token")
 	}
 
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
 		return "", err
 	}
 
-	ciphertext := aesGCM.Seal(nonce, nonce, []byte(token), nil)
-	return base64.URLEncoding.EncodeToString(ciphertext), nil
+	return string(plaintext), nil
 }
@@ -1,5 +1 @@
-CREATE TABLE IF NOT EXISTS registrations (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    token TEXT UNIQUE,
-    used BOOLEAN DEFAULT FALSE
-);
+DROP TABLE IF EXISTS registrations;
@@ -1 +1,5 @@
-DROP TABLE IF EXISTS registrations;
+CREATE TABLE IF NOT EXISTS registrations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE,
+    used BOOLEAN DEFAULT FALSE
+);
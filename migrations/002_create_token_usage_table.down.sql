@@ -1,6 +1 @@
-CREATE TABLE IF NOT EXISTS token_usage (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    token TEXT UNIQUE,
-    username TEXT,
-    phone TEXT
-);
+DROP TABLE IF EXISTS token_usage;
@@ -1 +1,6 @@
-DROP TABLE IF EXISTS token_usage;
+CREATE TABLE IF NOT EXISTS token_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE,
+    username TEXT,
+    phone TEXT
+);
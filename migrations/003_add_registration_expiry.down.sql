@@ -0,0 +1,2 @@
+ALTER TABLE registrations DROP COLUMN expires_at;
+ALTER TABLE registrations DROP COLUMN created_at;
@@ -0,0 +1,16 @@
+-- Базы, созданные до исправления миграций 001/002, остались без таблиц
+CREATE TABLE IF NOT EXISTS registrations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE,
+    used BOOLEAN DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS token_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE,
+    username TEXT,
+    phone TEXT
+);
+
+ALTER TABLE registrations ADD COLUMN created_at DATETIME;
+ALTER TABLE registrations ADD COLUMN expires_at DATETIME;
@@ -0,0 +1 @@
+ALTER TABLE registrations ADD COLUMN batch_label TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE registrations DROP COLUMN campaign_id;
+DROP TABLE IF EXISTS campaigns;
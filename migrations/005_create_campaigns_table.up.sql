@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT UNIQUE NOT NULL,
+    bonus_amount INTEGER NOT NULL,
+    client_group_id INTEGER NOT NULL,
+    success_text TEXT NOT NULL DEFAULT '',
+    created_at DATETIME
+);
+
+-- Кампания по умолчанию повторяет прежние жестко заданные значения
+INSERT INTO campaigns (id, code, bonus_amount, client_group_id, success_text)
+VALUES (1, 'default', 1000, 2, 'Теперь на вашем счёте 1000 бонусных баллов, которые можно потратить на кофе в нашей кофейне.');
+
+ALTER TABLE registrations ADD COLUMN campaign_id INTEGER REFERENCES campaigns(id);
+UPDATE registrations SET campaign_id = 1;
@@ -0,0 +1,4 @@
+ALTER TABLE registrations DROP COLUMN revoked_at;
+ALTER TABLE registrations DROP COLUMN revoked_by;
+ALTER TABLE registrations DROP COLUMN revoked_reason;
+ALTER TABLE registrations DROP COLUMN revoked;
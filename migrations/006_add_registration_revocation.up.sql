@@ -0,0 +1,4 @@
+ALTER TABLE registrations ADD COLUMN revoked BOOLEAN DEFAULT FALSE;
+ALTER TABLE registrations ADD COLUMN revoked_reason TEXT;
+ALTER TABLE registrations ADD COLUMN revoked_by INTEGER;
+ALTER TABLE registrations ADD COLUMN revoked_at DATETIME;
@@ -0,0 +1,4 @@
+ALTER TABLE token_usage DROP COLUMN created_at;
+ALTER TABLE token_usage DROP COLUMN client_id;
+DROP INDEX IF EXISTS idx_registration_jobs_state;
+DROP TABLE IF EXISTS registration_jobs;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS registration_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    phone TEXT NOT NULL,
+    birthday TEXT NOT NULL,
+    campaign_id INTEGER NOT NULL REFERENCES campaigns(id),
+    state TEXT NOT NULL,
+    failed BOOLEAN NOT NULL DEFAULT FALSE,
+    client_id INTEGER,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_jobs_state ON registration_jobs (state, failed, next_attempt_at);
+
+ALTER TABLE token_usage ADD COLUMN client_id INTEGER;
+ALTER TABLE token_usage ADD COLUMN created_at DATETIME;
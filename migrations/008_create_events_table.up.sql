@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    token TEXT,
+    campaign_id INTEGER REFERENCES campaigns(id),
+    reason TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events (created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admin_settings;
+ALTER TABLE registration_jobs DROP COLUMN client_existed;
+ALTER TABLE registrations DROP COLUMN issued_by;
@@ -0,0 +1,8 @@
+ALTER TABLE registrations ADD COLUMN issued_by INTEGER;
+
+ALTER TABLE registration_jobs ADD COLUMN client_existed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS admin_settings (
+    admin_id INTEGER PRIMARY KEY,
+    notifications BOOLEAN NOT NULL DEFAULT TRUE
+);
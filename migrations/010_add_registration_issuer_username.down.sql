@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_registrations_issued_by;
+ALTER TABLE registrations DROP COLUMN issued_by_username;
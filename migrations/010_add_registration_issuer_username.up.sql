@@ -0,0 +1,3 @@
+ALTER TABLE registrations ADD COLUMN issued_by_username TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_registrations_issued_by ON registrations (issued_by, created_at);
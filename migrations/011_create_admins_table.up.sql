@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS admins (
+    user_id INTEGER PRIMARY KEY,
+    username TEXT,
+    role TEXT NOT NULL,
+    added_by INTEGER,
+    created_at DATETIME
+);
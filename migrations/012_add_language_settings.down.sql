@@ -0,0 +1,2 @@
+ALTER TABLE admin_settings DROP COLUMN language;
+ALTER TABLE campaigns DROP COLUMN language;
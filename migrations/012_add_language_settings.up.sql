@@ -0,0 +1,3 @@
+-- Пустая строка — язык не выбран, он определяется по браузеру или Telegram
+ALTER TABLE campaigns ADD COLUMN language TEXT NOT NULL DEFAULT '';
+ALTER TABLE admin_settings ADD COLUMN language TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Удаленную часть текста ошибок восстановить нельзя
+SELECT 1;
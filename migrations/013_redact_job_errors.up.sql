@@ -0,0 +1,5 @@
+-- Ошибки запросов к Poster раньше сохранялись вместе с адресом, в котором передается токен.
+-- Адрес обрезается до параметров запроса.
+UPDATE registration_jobs
+SET last_error = substr(last_error, 1, instr(last_error, '?') - 1) || '…'
+WHERE last_error LIKE '%token=%' AND instr(last_error, '?') > 0;